@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mojotx/cal/pkg/calendar"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the config file, by default $XDG_CONFIG_HOME/cal/config.yaml. It
+// sets the defaults of the command line options.
+type config struct {
+	WeekStart   string      `yaml:"week-start"`
+	WeekNumbers string      `yaml:"week-numbers"`
+	Locale      string      `yaml:"locale"`
+	Reform      string      `yaml:"reform"`
+	Color       string      `yaml:"color"`
+	Theme       themeConfig `yaml:"theme"`
+	Weekend     string      `yaml:"weekend"`
+	Holidays    string      `yaml:"holidays"`
+	Layout      string      `yaml:"layout"`
+	Columns     int         `yaml:"columns"`
+	OutsideDays bool        `yaml:"outside-days"`
+	Events      string      `yaml:"events,omitempty"`
+	EventLegend bool        `yaml:"event-legend"`
+}
+
+// themeConfig is a theme in the config file: the name of a built-in theme,
+// the path of a theme file or the theme written out as a mapping.
+type themeConfig struct {
+	name  string
+	theme *calendar.Theme
+}
+
+// UnmarshalYAML reads a theme name, path or mapping.
+func (t *themeConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		t.name = node.Value
+		return nil
+	}
+	theme := *calendar.DefaultTheme
+	if err := node.Decode(&theme); err != nil {
+		return err
+	}
+	t.theme = &theme
+	return nil
+}
+
+// MarshalYAML writes the theme name, or the theme itself if it has none.
+func (t themeConfig) MarshalYAML() (any, error) {
+	if t.name == "" && t.theme != nil {
+		return t.theme, nil
+	}
+	return cmp.Or(t.name, "default"), nil
+}
+
+// configPath returns the path of the config file: the CAL_CONFIG environment
+// variable, else cal/config.yaml in $XDG_CONFIG_HOME or ~/.config, on every
+// system. It is empty if neither is known.
+func configPath() string {
+	if path := os.Getenv("CAL_CONFIG"); path != "" {
+		return path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "cal", "config.yaml")
+}
+
+// loadConfig reads and validates the config file at path. A missing file is
+// an empty config.
+func loadConfig(path string) (*config, error) {
+	var c config
+	if path == "" {
+		return &c, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// Theme, holiday and event files are found relative to the config file.
+	dir := filepath.Dir(path)
+	if c.Theme.name != "" && !filepath.IsAbs(c.Theme.name) {
+		if _, err := calendar.LookupTheme(c.Theme.name); err != nil {
+			c.Theme.name = filepath.Join(dir, c.Theme.name)
+		}
+	}
+	if c.Holidays != "" && !strings.EqualFold(c.Holidays, "none") {
+		names := strings.Split(c.Holidays, ",")
+		for i, name := range names {
+			name = strings.TrimSpace(name)
+			if _, err := calendar.LookupRegion(name); err != nil && !filepath.IsAbs(name) {
+				name = filepath.Join(dir, name)
+			}
+			names[i] = name
+		}
+		c.Holidays = strings.Join(names, ",")
+	}
+	if c.Events != "" {
+		paths := strings.Split(c.Events, ",")
+		for i, p := range paths {
+			if p = strings.TrimSpace(p); !filepath.IsAbs(p) {
+				p = filepath.Join(dir, p)
+			}
+			paths[i] = p
+		}
+		c.Events = strings.Join(paths, ",")
+	}
+	return &c, nil
+}
+
+// validate checks the values of the config file, so that errors name the
+// setting rather than a command line option. Holiday and event files are only
+// checked for their syntax here: they are read once the options have been
+// applied, which may replace them.
+func (c *config) validate() error {
+	checks := []struct {
+		key   string
+		value string
+		parse func(string) error
+	}{
+		{"week-start", c.WeekStart, func(s string) error { _, err := calendar.ParseWeekday(s); return err }},
+		{"week-numbers", c.WeekNumbers, func(s string) error { _, err := calendar.ParseWeekNumbering(s); return err }},
+		{"locale", c.Locale, func(s string) error { _, err := calendar.LookupLocale(s); return err }},
+		{"reform", c.Reform, func(s string) error { _, err := calendar.ParseReform(s); return err }},
+		{"color", c.Color, func(s string) error { _, err := calendar.ParseColorMode(s); return err }},
+		{"weekend", c.Weekend, func(s string) error { _, err := calendar.ParseWeekend(s); return err }},
+		{"layout", c.Layout, func(s string) error { _, err := calendar.ParseLayout(s); return err }},
+		{"holidays", c.Holidays, checkNames},
+		{"events", c.Events, checkNames},
+	}
+	for _, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		if err := check.parse(check.value); err != nil {
+			return fmt.Errorf("%s: %w", check.key, err)
+		}
+	}
+	if c.Columns < 0 {
+		return fmt.Errorf("columns: %d is negative", c.Columns)
+	}
+	return nil
+}
+
+// checkNames checks that s is a comma-separated list of names or paths
+// without empty ones.
+func checkNames(s string) error {
+	for _, name := range strings.Split(s, ",") {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("empty name in %q", s)
+		}
+	}
+	return nil
+}
+
+// applyTo fills in the options of f that are set in the config file but not
+// on the command line parsed by fs.
+func (c *config) applyTo(f *calendarFlags, fs *flagSet) {
+	if c.WeekStart != "" && !fs.changed("monday") && !fs.changed("sunday") && !fs.changed("week-start") {
+		f.weekStart = c.WeekStart
+	}
+	if c.WeekNumbers != "" && !fs.changed("week-numbers") && !fs.changed("week-numbering") {
+		f.numbering = c.WeekNumbers
+	}
+	if c.Locale != "" && !fs.changed("locale") {
+		f.locale = c.Locale
+	}
+	if c.Reform != "" && !fs.changed("reform") {
+		f.reform = c.Reform
+	}
+	if c.Color != "" && !fs.changed("color") {
+		f.color = c.Color
+	}
+	// CAL_THEME takes precedence over the config file.
+	if !fs.changed("theme") && os.Getenv("CAL_THEME") == "" {
+		f.theme, f.inlineTheme = c.Theme.name, c.Theme.theme
+	}
+	if c.Weekend != "" && !fs.changed("weekend") {
+		f.weekend = c.Weekend
+	}
+	if c.Holidays != "" && !fs.changed("holidays") {
+		f.holidays = c.Holidays
+	}
+	if c.Layout != "" && !fs.changed("vertical") {
+		layout, _ := calendar.ParseLayout(c.Layout)
+		f.vertical = layout == calendar.LayoutVertical
+	}
+	if c.Columns != 0 && !fs.changed("columns") {
+		f.columns = c.Columns
+	}
+	if c.OutsideDays && !fs.changed("outside-days") {
+		f.outside = true
+	}
+	if c.Events != "" && !fs.changed("events") {
+		f.events = c.Events
+	}
+	if c.EventLegend && !fs.changed("legend") {
+		f.legend = true
+	}
+}
+
+// config returns the settings in the form of the config file.
+func (s *settings) config() *config {
+	weekend := make([]string, len(s.weekend))
+	for i, d := range s.weekend {
+		weekend[i] = strings.ToLower(d.String()[:3])
+	}
+	return &config{
+		WeekStart:   strings.ToLower(s.firstWeekday.String()),
+		WeekNumbers: s.weekNumbers.String(),
+		Locale:      s.locale.Name,
+		Reform:      s.reform.String(),
+		Color:       s.color.String(),
+		Theme:       themeConfig{name: s.themeName, theme: s.theme},
+		Weekend:     cmp.Or(strings.Join(weekend, ","), "none"),
+		Holidays:    cmp.Or(strings.Join(s.holidayNames, ","), "none"),
+		Layout:      s.layout.String(),
+		Columns:     s.columns,
+		OutsideDays: s.outsideDays,
+		Events:      strings.Join(s.eventFiles, ","),
+		EventLegend: s.legend,
+	}
+}
+
+// runConfig runs "cal config show [options]", which prints the effective
+// settings given the config file, the environment and the options, and "cal
+// config path", which prints the path of the config file.
+func runConfig(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("missing config command, expected show or path")
+	}
+	switch args[0] {
+	case "-h", "--help", "-help":
+		printConfigHelp(stdout)
+		return nil
+	case "path":
+		if len(args) == 2 && (args[1] == "-h" || args[1] == "--help") {
+			printConfigHelp(stdout)
+			return nil
+		}
+		if len(args) > 1 {
+			return usageErrorf("too many arguments")
+		}
+		fmt.Fprintln(stdout, configPath())
+		return nil
+	case "show":
+		f, positional, err := parseCalendarArgs(args[1:])
+		if errors.Is(err, flag.ErrHelp) || (err == nil && f.help) {
+			printConfigHelp(stdout)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(positional) > 0 {
+			return usageErrorf("too many arguments")
+		}
+		s, err := f.settings()
+		if err != nil {
+			return err
+		}
+		path := configPath()
+		if _, err := os.Stat(path); err != nil {
+			path += " (not found)"
+		}
+		fmt.Fprintf(stdout, "# config file: %s\n", path)
+		enc := yaml.NewEncoder(stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(s.config()); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return usageErrorf("unknown config command %q, expected show or path", args[0])
+	}
+}
+
+// printConfigHelp writes the usage of the config command.
+func printConfigHelp(stdout io.Writer) {
+	var f calendarFlags
+	fs := newCalendarFlagSet(&f)
+	fmt.Fprintln(stdout, "Usage:")
+	fmt.Fprintln(stdout, "  cal config show [options]")
+	fmt.Fprintln(stdout, "  cal config path")
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "show prints the settings in effect given the config file, the environment")
+	fmt.Fprintln(stdout, "and the options of the calendar, in the form of the config file. path prints")
+	fmt.Fprintln(stdout, "the path of the config file.")
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Options:")
+	fs.printDefaults(stdout)
+}
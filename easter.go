@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mojotx/cal/pkg/calendar"
+)
+
+// runEaster prints the date of Easter Sunday, like "ncal -e" and "ncal -o".
+func runEaster(args []string, stdout io.Writer) error {
+	var orthodox bool
+	var locale, today string
+	reform := calendar.Reform1752.String()
+	fs := newFlagSet("cal easter")
+	fs.boolVar(&orthodox, "o", "orthodox", "print Orthodox instead of Western Easter")
+	fs.stringVar(&locale, "", "locale", "NAME", "write the date in language NAME")
+	fs.stringVar(&reform, "", "reform", "WHEN", "switch from the Julian calendar in 1582, 1752 (default), 1918 or never")
+	fs.stringVar(&today, "", "today", "DATE", "treat DATE (YYYY-MM-DD) as today, which selects the default year")
+	positional, err := fs.parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(stdout, "Usage:")
+		fmt.Fprintln(stdout, "  cal easter [options] [year]")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Prints the date of Easter Sunday in the year, by default the current one.")
+		fmt.Fprintln(stdout, "Before the calendar reform, Western Easter is reckoned and both dates are")
+		fmt.Fprintln(stdout, "written in the Julian calendar.")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Options:")
+		fs.printDefaults(stdout)
+		return nil
+	}
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	f := calendarFlags{today: today, locale: locale, reform: reform}
+	if err := completeFromConfig(&f, fs); err != nil {
+		return err
+	}
+	l, err := lookupLocale(f.locale)
+	if err != nil {
+		return err
+	}
+	r, err := calendar.ParseReform(f.reform)
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+	now, err := f.todayDate()
+	if err != nil {
+		return err
+	}
+
+	year := now.Year()
+	switch len(positional) {
+	case 0:
+	case 1:
+		if year, err = parseYear(positional[0]); err != nil {
+			return err
+		}
+	default:
+		return usageErrorf("too many arguments")
+	}
+
+	easter := r.Easter(year)
+	if orthodox {
+		easter = calendar.OrthodoxEaster(year)
+	}
+	y, m, d := r.Date(easter)
+	fmt.Fprintln(stdout, l.FormatDate(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)))
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mojotx/cal/pkg/calendar"
+	"github.com/mojotx/cal/pkg/ical"
+)
+
+// loadEvents reads a comma-separated list of iCalendar files. It also returns
+// the paths of the files.
+func loadEvents(s string) ([]*ical.Calendar, []string, error) {
+	var calendars []*ical.Calendar
+	var paths []string
+	for _, path := range strings.Split(s, ",") {
+		path = strings.TrimSpace(path)
+		c, err := readICalendar(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		calendars = append(calendars, c)
+		paths = append(paths, path)
+	}
+	return calendars, paths, nil
+}
+
+// readICalendar parses the iCalendar file at path.
+func readICalendar(path string) (*ical.Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := ical.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// eventsIn returns the events of the calendars in the count months starting
+// with the month of first, with recurring events expanded. Timed events are
+// placed on the days they take up in the local time zone.
+func eventsIn(calendars []*ical.Calendar, first time.Time, count int) []calendar.Event {
+	// A day more on each side covers the days that time zones shift events to.
+	from := time.Date(first.Year(), first.Month(), 0, 0, 0, 0, 0, time.Local)
+	to := time.Date(first.Year(), first.Month()+time.Month(count), 2, 0, 0, 0, 0, time.Local)
+	var events []calendar.Event
+	for _, c := range calendars {
+		for _, e := range c.Between(from, to) {
+			start, end := e.Days(time.Local)
+			events = append(events, calendar.Event{Name: e.Summary, Start: start, End: end})
+		}
+	}
+	return events
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mojotx/cal/pkg/calendar"
+	"github.com/mojotx/cal/pkg/ical"
+	"github.com/mojotx/cal/pkg/rrule"
+)
+
+// exportFlags holds the options of the export command.
+type exportFlags struct {
+	region   string
+	easter   bool
+	orthodox bool
+	rule     string
+	start    string
+	name     string
+	days     int
+	years    int
+	output   string
+	title    string
+	today    string
+}
+
+// runExport writes holidays, Easter and the dates of a recurrence rule as an
+// iCalendar file.
+func runExport(args []string, stdout io.Writer) error {
+	var e exportFlags
+	fs := newFlagSet("cal export")
+	fs.stringVar(&e.region, "r", "region", "REGIONS", "export the holidays of REGIONS, e.g. us or gb,de ("+strings.Join(calendar.Regions(), ", ")+") or holiday files (default from the config file)")
+	fs.boolVar(&e.easter, "", "easter", "export Western Easter Sunday")
+	fs.boolVar(&e.orthodox, "", "orthodox-easter", "export Orthodox Easter Sunday")
+	fs.stringVar(&e.rule, "", "rule", "RULE", `export the dates of the recurrence RULE, e.g. "FREQ=WEEKLY;INTERVAL=2" for sprints`)
+	fs.stringVar(&e.start, "", "start", "DATE", "start RULE on DATE (YYYY-MM-DD) instead of the first day exported")
+	fs.stringVar(&e.name, "", "name", "NAME", `name the events of RULE NAME, in which %d is replaced by their number counted from the start, e.g. "Sprint %d" (default "Event")`)
+	fs.intVar(&e.days, "", "days", "N", "make the events of RULE N days long (default 1)")
+	fs.intVar(&e.years, "y", "years", "N", "export N years starting with the year (default 1)")
+	fs.stringVar(&e.output, "o", "output", "FILE", "write to FILE instead of standard output")
+	fs.stringVar(&e.title, "", "calendar-name", "NAME", "name the calendar NAME in calendar applications")
+	fs.stringVar(&e.today, "", "today", "DATE", "treat DATE (YYYY-MM-DD) as today, which selects the default year")
+	positional, err := fs.parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(stdout, "Usage:")
+		fmt.Fprintln(stdout, "  cal export [options] [[month] year]")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Writes the holidays, Easter and the dates of a recurrence rule in the year, by")
+		fmt.Fprintln(stdout, "default the current one, or in the month as an iCalendar (.ics) file, which")
+		fmt.Fprintln(stdout, "calendar applications can import or subscribe to.")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Options:")
+		fs.printDefaults(stdout)
+		return nil
+	}
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	f := calendarFlags{today: e.today}
+	if err := completeFromConfig(&f, fs); err != nil {
+		return err
+	}
+	regions, _, err := parseRegions(cmp.Or(e.region, f.holidays, "none"))
+	if err != nil {
+		return err
+	}
+	now, err := f.todayDate()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(regions) == 0 && !e.easter && !e.orthodox && e.rule == "":
+		return usageErrorf("nothing to export, use --region, --easter, --orthodox-easter or --rule")
+	case e.rule == "" && (e.start != "" || e.name != "" || e.days != 0):
+		return usageErrorf("--start, --name and --days need --rule")
+	case e.days < 0:
+		return usageErrorf("--days must not be negative")
+	case e.years < 0:
+		return usageErrorf("--years must not be negative")
+	}
+
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	switch len(positional) {
+	case 0:
+	case 1:
+		year, err := parseYear(positional[0])
+		if err != nil {
+			return err
+		}
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	case 2:
+		month, err := parseMonth(positional[0])
+		if err != nil {
+			return err
+		}
+		year, err := parseYear(positional[1])
+		if err != nil {
+			return err
+		}
+		if e.years != 0 {
+			return usageErrorf("--years cannot be combined with a month")
+		}
+		from = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return usageErrorf("too many arguments")
+	}
+	to := from.AddDate(max(e.years, 1), 0, 0)
+	if len(positional) == 2 {
+		to = from.AddDate(0, 1, 0)
+	}
+
+	c := &ical.Calendar{Name: e.title}
+	c.Events = append(c.Events, holidayEvents(regions, from, to)...)
+	if e.easter {
+		c.Events = append(c.Events, easterEvents("Easter Sunday", calendar.Easter, from, to)...)
+	}
+	if e.orthodox {
+		c.Events = append(c.Events, easterEvents("Orthodox Easter Sunday", calendar.OrthodoxEaster, from, to)...)
+	}
+	if e.rule != "" {
+		events, err := e.ruleEvents(from, to)
+		if err != nil {
+			return err
+		}
+		c.Events = append(c.Events, events...)
+	}
+	sort.SliceStable(c.Events, func(i, j int) bool { return c.Events[i].Start.Before(c.Events[j].Start) })
+	if len(c.Events) == 0 {
+		return fmt.Errorf("nothing to export in %s", exportPeriod(from, to, len(positional) == 2))
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, c); err != nil {
+		return err
+	}
+	if e.output != "" {
+		return os.WriteFile(e.output, buf.Bytes(), 0o644)
+	}
+	_, err = buf.WriteTo(stdout)
+	return err
+}
+
+// exportPeriod names the exported period: a month, a year or a span of years.
+func exportPeriod(from, to time.Time, month bool) string {
+	switch last := to.AddDate(0, 0, -1).Year(); {
+	case month:
+		return from.Format("January 2006")
+	case last == from.Year():
+		return strconv.Itoa(from.Year())
+	default:
+		return fmt.Sprintf("%d-%d", from.Year(), last)
+	}
+}
+
+// holidayEvents returns the holidays of the regions from from up to to as
+// all-day events. A holiday observed on another day gets a second event on
+// that day.
+func holidayEvents(regions []*calendar.Region, from, to time.Time) []*ical.Event {
+	var events []*ical.Event
+	for _, r := range regions {
+		// Holidays near the turn of a year can be observed in the year before
+		// or after.
+		for year := from.Year() - 1; year <= to.Year(); year++ {
+			for _, h := range r.Holidays(year) {
+				if inRange(h.Date, from, to) {
+					events = append(events, dayEvent(h.Name, h.Date, r.Name, r.Code, h.Name))
+				}
+				if !h.Observed.Equal(h.Date) && inRange(h.Observed, from, to) {
+					events = append(events, dayEvent(h.Name+" (observed)", h.Observed, r.Name, r.Code, h.Name, "observed"))
+				}
+			}
+		}
+	}
+	return events
+}
+
+// easterEvents returns the Easter Sundays given by easter from from up to to
+// as all-day events.
+func easterEvents(name string, easter func(year int) time.Time, from, to time.Time) []*ical.Event {
+	var events []*ical.Event
+	for year := from.Year(); year <= to.AddDate(0, 0, -1).Year(); year++ {
+		if date := easter(year); inRange(date, from, to) {
+			events = append(events, dayEvent(name, date, "", name))
+		}
+	}
+	return events
+}
+
+// ruleEvents returns the occurrences of the recurrence rule that take place
+// between from and to as all-day events.
+func (e *exportFlags) ruleEvents(from, to time.Time) ([]*ical.Event, error) {
+	r, err := rrule.Parse(e.rule)
+	if err != nil {
+		return nil, usageErrorf("invalid --rule: %s", err)
+	}
+	start := from
+	if e.start != "" {
+		if start, err = time.Parse(time.DateOnly, e.start); err != nil {
+			return nil, usageErrorf("invalid --start %q, expected YYYY-MM-DD", e.start)
+		}
+	}
+	name, days := cmp.Or(e.name, "Event"), max(e.days, 1)
+
+	occurrences := r.Between(start, start, to)
+	// Unlike in iCalendar, the start is only a date if the rule selects it,
+	// so that a rule such as the last Friday of the month does not also
+	// export the first day.
+	if !r.Synchronized(start) {
+		if r.Count != 0 {
+			r.Count++
+			occurrences = r.Between(start, start, to)
+		}
+		if len(occurrences) > 0 {
+			occurrences = occurrences[1:]
+		}
+	}
+	var events []*ical.Event
+	// The occurrences are numbered from the start, also before the range.
+	for i, t := range occurrences {
+		event := dayEvent(strings.ReplaceAll(name, "%d", strconv.Itoa(i+1)), t, "", strings.ReplaceAll(name, "%d", ""))
+		event.End = t.AddDate(0, 0, days)
+		if event.End.After(from) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// dayEvent returns an all-day event on date. Its UID is made of the date
+// and the words of uidParts, so that it stays the same when the file is
+// exported again.
+func dayEvent(name string, date time.Time, description string, uidParts ...string) *ical.Event {
+	words := append([]string{date.Format("20060102")}, uidParts...)
+	uid := strings.Join(strings.FieldsFunc(strings.ToLower(strings.Join(words, " ")), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+	return &ical.Event{
+		UID:         uid + "@cal",
+		Summary:     name,
+		Description: description,
+		Start:       date,
+		End:         date.AddDate(0, 0, 1),
+		AllDay:      true,
+	}
+}
+
+// inRange reports whether t is from from up to but not including to.
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
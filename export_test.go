@@ -0,0 +1,186 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mojotx/cal/pkg/ical"
+	"github.com/stretchr/testify/assert"
+)
+
+// exportedEvent is an event of an export as "date summary uid".
+func exportedEvent(e *ical.Event) string {
+	return e.Start.Format(time.DateOnly) + " " + e.Summary + " " + e.UID
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRuleEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    exportFlags
+		from, to time.Time
+		expected []string
+	}{
+		{
+			name:  "numbered from a start before the range",
+			flags: exportFlags{rule: "FREQ=WEEKLY;INTERVAL=2", start: "2026-01-05", name: "Sprint %d", days: 14},
+			from:  date(2026, time.March, 1),
+			to:    date(2026, time.April, 1),
+			expected: []string{
+				"2026-02-16 Sprint 4 20260216-sprint@cal",
+				"2026-03-02 Sprint 5 20260302-sprint@cal",
+				"2026-03-16 Sprint 6 20260316-sprint@cal",
+				"2026-03-30 Sprint 7 20260330-sprint@cal",
+			},
+		},
+		{
+			name:  "start that the rule does not select",
+			flags: exportFlags{rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2", start: "2026-10-01", name: "Review %d"},
+			from:  date(2026, time.January, 1),
+			to:    date(2027, time.January, 1),
+			expected: []string{
+				"2026-10-30 Review 1 20261030-review@cal",
+				"2026-11-27 Review 2 20261127-review@cal",
+			},
+		},
+		{
+			name:     "default name and start",
+			flags:    exportFlags{rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=1"},
+			from:     date(2026, time.January, 1),
+			to:       date(2028, time.January, 1),
+			expected: []string{"2026-02-01 Event 20260201-event@cal", "2027-02-01 Event 20270201-event@cal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := tt.flags.ruleEvents(tt.from, tt.to)
+			assert.NoError(t, err)
+			actual := make([]string, len(events))
+			for i, e := range events {
+				actual[i] = exportedEvent(e)
+				assert.Equal(t, e.Start.AddDate(0, 0, max(tt.flags.days, 1)), e.End)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRuleEventsErrors(t *testing.T) {
+	_, err := (&exportFlags{rule: "FREQ=HOURLY"}).ruleEvents(date(2026, time.January, 1), date(2027, time.January, 1))
+	assert.ErrorContains(t, err, "invalid --rule")
+	_, err = (&exportFlags{rule: "FREQ=DAILY", start: "2026-13-01"}).ruleEvents(date(2026, time.January, 1), date(2027, time.January, 1))
+	assert.EqualError(t, err, `invalid --start "2026-13-01", expected YYYY-MM-DD`)
+}
+
+func TestHolidayEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		regions  []string
+		from, to time.Time
+		expected []string
+	}{
+		{
+			name:    "observed on another day",
+			regions: []string{"us"},
+			from:    date(2026, time.July, 1),
+			to:      date(2026, time.August, 1),
+			expected: []string{
+				"2026-07-04 Independence Day 20260704-us-independence-day@cal",
+				"2026-07-03 Independence Day (observed) 20260703-us-independence-day-observed@cal",
+			},
+		},
+		{
+			name:    "observed in the year before",
+			regions: []string{"us"},
+			from:    date(2021, time.December, 1),
+			to:      date(2022, time.January, 1),
+			expected: []string{
+				"2021-12-25 Christmas Day 20211225-us-christmas-day@cal",
+				"2021-12-24 Christmas Day (observed) 20211224-us-christmas-day-observed@cal",
+				"2021-12-31 New Year's Day (observed) 20211231-us-new-year-s-day-observed@cal",
+			},
+		},
+		{
+			name:    "same holiday in several regions",
+			regions: []string{"us", "gb"},
+			from:    date(2026, time.December, 25),
+			to:      date(2026, time.December, 26),
+			expected: []string{
+				"2026-12-25 Christmas Day 20261225-us-christmas-day@cal",
+				"2026-12-25 Christmas Day 20261225-gb-christmas-day@cal",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := holidayEvents(lookupRegions(t, tt.regions...), tt.from, tt.to)
+			actual := make([]string, len(events))
+			for i, e := range events {
+				actual[i] = exportedEvent(e)
+				assert.True(t, e.AllDay)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	setEnv(t)
+	args := []string{"export", "-r", "us,gb", "--easter", "--orthodox-easter", "--rule", "FREQ=WEEKLY;INTERVAL=2", "--start", "2025-12-29", "--name", "Sprint %d", "2026"}
+	code, first, stderr := runCal(args...)
+	assert.Equal(t, exitOK, code, stderr)
+	c, err := ical.Parse(strings.NewReader(first))
+	assert.NoError(t, err)
+
+	// The UIDs are unique and the same in every export.
+	uids := make(map[string]bool)
+	for _, e := range c.Events {
+		assert.False(t, uids[e.UID], "duplicate UID %s", e.UID)
+		uids[e.UID] = true
+	}
+	assert.True(t, uids["20260405-easter-sunday@cal"])
+	assert.True(t, uids["20260412-orthodox-easter-sunday@cal"])
+	assert.True(t, uids["20261228-gb-boxing-day-observed@cal"])
+
+	t.Setenv("CAL_TODAY", "2026-10-17")
+	code, second, _ := runCal(args...)
+	assert.Equal(t, exitOK, code)
+	again, err := ical.Parse(strings.NewReader(second))
+	assert.NoError(t, err)
+	if assert.Len(t, again.Events, len(c.Events)) {
+		for i, e := range again.Events {
+			assert.Equal(t, c.Events[i].UID, e.UID)
+		}
+	}
+}
+
+func TestRunExportErrors(t *testing.T) {
+	setEnv(t)
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"nothing selected", []string{"export"}, exitUsage, "cal: nothing to export, use --region, --easter, --orthodox-easter or --rule"},
+		{"no holidays in the month", []string{"export", "-r", "us", "8", "2026"}, exitError, "cal: nothing to export in August 2026"},
+		{"rule starting after the range", []string{"export", "--rule", "FREQ=YEARLY", "--start", "2030-01-01", "-y", "2", "2026"}, exitError, "cal: nothing to export in 2026-2027"},
+		{"no Easter in the month", []string{"export", "--easter", "1", "2026"}, exitError, "cal: nothing to export in January 2026"},
+		{"start without rule", []string{"export", "--easter", "--start", "2026-01-01"}, exitUsage, "cal: --start, --name and --days need --rule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCal(tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Empty(t, stdout)
+			assert.Equal(t, tt.stderr, strings.SplitN(stderr, "\n", 2)[0])
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// flagDoc describes a single option for the help output.
+type flagDoc struct {
+	short string
+	long  string
+	arg   string
+	usage string
+}
+
+// flagSet wraps flag.FlagSet so that every option can be registered under a
+// short and a long name and documented once.
+type flagSet struct {
+	*flag.FlagSet
+	docs []flagDoc
+}
+
+// newFlagSet returns a flagSet that reports errors instead of exiting and
+// leaves printing usage to the caller.
+func newFlagSet(name string) *flagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return &flagSet{FlagSet: fs}
+}
+
+// names calls register for the short and long names that are set.
+func (fs *flagSet) names(short, long string, register func(name string)) {
+	if short != "" {
+		register(short)
+	}
+	if long != "" {
+		register(long)
+	}
+}
+
+// boolVar registers a boolean option.
+func (fs *flagSet) boolVar(p *bool, short, long, usage string) {
+	fs.names(short, long, func(name string) { fs.BoolVar(p, name, *p, usage) })
+	fs.docs = append(fs.docs, flagDoc{short: short, long: long, usage: usage})
+}
+
+// stringVar registers an option that takes a string argument.
+func (fs *flagSet) stringVar(p *string, short, long, arg, usage string) {
+	fs.names(short, long, func(name string) { fs.StringVar(p, name, *p, usage) })
+	fs.docs = append(fs.docs, flagDoc{short: short, long: long, arg: arg, usage: usage})
+}
+
+// intVar registers an option that takes an integer argument.
+func (fs *flagSet) intVar(p *int, short, long, arg, usage string) {
+	fs.names(short, long, func(name string) { fs.IntVar(p, name, *p, usage) })
+	fs.docs = append(fs.docs, flagDoc{short: short, long: long, arg: arg, usage: usage})
+}
+
+// parse parses args, allowing options to appear before, between and after
+// positional arguments and single-letter options to be combined, as in
+// "-wM". A "--" ends option processing.
+func (fs *flagSet) parse(args []string) ([]string, error) {
+	args = fs.splitShort(args)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		// flag.Parse consumes a lone "--" itself; everything after it is
+		// positional.
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// splitShort splits combined single-letter options such as "-3j" into "-3"
+// "-j". All but the last letter must be boolean options; the last one may
+// take the rest of the argument as its value, as in "-A2" or "-jA2", or else
+// the next argument. Arguments that are options on their own, values of
+// options and arguments after "--" are left alone.
+func (fs *flagSet) splitShort(args []string) []string {
+	var split []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(split, args[i:]...)
+		}
+		if f := fs.lookupOption(arg); f != nil {
+			split = append(split, arg)
+			// An option without "=" that is not boolean takes the next
+			// argument as its value.
+			if !isBoolFlag(f) && !strings.Contains(arg, "=") && i+1 < len(args) {
+				split = append(split, args[i+1])
+				i++
+			}
+			continue
+		}
+		options, takesNext, ok := fs.combined(arg)
+		if !ok {
+			split = append(split, arg)
+			continue
+		}
+		split = append(split, options...)
+		if takesNext && i+1 < len(args) {
+			split = append(split, args[i+1])
+			i++
+		}
+	}
+	return split
+}
+
+// lookupOption returns the option that arg names, such as "-w", "--weeks" or
+// "--locale=de", or nil if arg is not an option.
+func (fs *flagSet) lookupOption(arg string) *flag.Flag {
+	name, ok := strings.CutPrefix(arg, "-")
+	if !ok {
+		return nil
+	}
+	name = strings.TrimPrefix(name, "-")
+	name, _, _ = strings.Cut(name, "=")
+	return fs.Lookup(name)
+}
+
+// combined splits arg if it combines single-letter options, all of them
+// boolean up to one that takes a value: the rest of arg, if any. It also
+// reports whether that option takes the next argument as its value instead.
+func (fs *flagSet) combined(arg string) (options []string, takesNext, ok bool) {
+	name, ok := strings.CutPrefix(arg, "-")
+	if !ok || len(name) < 2 || strings.HasPrefix(name, "-") {
+		return nil, false, false
+	}
+	for i, l := range name {
+		f := fs.Lookup(string(l))
+		if f == nil {
+			return nil, false, false
+		}
+		options = append(options, "-"+string(l))
+		if !isBoolFlag(f) {
+			if value := name[i+len(string(l)):]; value != "" {
+				return append(options, value), false, true
+			}
+			return options, true, true
+		}
+	}
+	return options, false, true
+}
+
+// isBoolFlag reports whether f is a boolean option, which takes no value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// changed reports whether the option with the given long name was set on the
+// command line, under its short or its long name.
+func (fs *flagSet) changed(long string) bool {
+	names := map[string]bool{long: true}
+	for _, d := range fs.docs {
+		if d.long == long && d.short != "" {
+			names[d.short] = true
+		}
+	}
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || names[f.Name]
+	})
+	return set
+}
+
+// printDefaults writes the documented options to w.
+func (fs *flagSet) printDefaults(w io.Writer) {
+	labels := make([]string, len(fs.docs))
+	width := 0
+	for i, d := range fs.docs {
+		var label string
+		switch {
+		case d.short == "":
+			label = "    --" + d.long
+		case d.long == "":
+			label = "-" + d.short
+		default:
+			label = "-" + d.short + ", --" + d.long
+		}
+		if d.arg != "" {
+			label += " " + d.arg
+		}
+		labels[i] = label
+		width = max(width, len(label))
+	}
+	for i, d := range fs.docs {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, labels[i], d.usage)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mojotx/cal/pkg/calendar"
+)
+
+// holidayRow is a line of the holiday listing.
+type holidayRow struct {
+	Date     string `json:"date"`
+	Weekday  string `json:"weekday"`
+	Name     string `json:"name"`
+	Observed string `json:"observed"`
+	Region   string `json:"region"`
+}
+
+// runHolidays lists the holidays of a year or a month.
+func runHolidays(args []string, stdout io.Writer) error {
+	var region, format, today string
+	fs := newFlagSet("cal holidays")
+	fs.stringVar(&region, "r", "region", "REGIONS", "list the holidays of REGIONS, e.g. us or gb,de ("+strings.Join(calendar.Regions(), ", ")+") or holiday files (default from the config file)")
+	fs.stringVar(&format, "f", "format", "FORMAT", "print a table (text, the default), json or csv")
+	fs.stringVar(&today, "", "today", "DATE", "treat DATE (YYYY-MM-DD) as today, which selects the default year")
+	positional, err := fs.parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(stdout, "Usage:")
+		fmt.Fprintln(stdout, "  cal holidays [options] [[month] year]")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Lists the holidays of the year, by default the current one, or of the month")
+		fmt.Fprintln(stdout, "in date order, with the days they are observed on.")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Options:")
+		fs.printDefaults(stdout)
+		return nil
+	}
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	f := calendarFlags{today: today}
+	if err := completeFromConfig(&f, fs); err != nil {
+		return err
+	}
+	regions, _, err := parseRegions(cmp.Or(region, f.holidays, "none"))
+	if err != nil {
+		return err
+	}
+	if len(regions) == 0 {
+		return usageErrorf("no holiday region, use --region or set holidays in the config file")
+	}
+	now, err := f.todayDate()
+	if err != nil {
+		return err
+	}
+
+	year, month := now.Year(), time.Month(0)
+	switch len(positional) {
+	case 0:
+	case 1:
+		if year, err = parseYear(positional[0]); err != nil {
+			return err
+		}
+	case 2:
+		if month, err = parseMonth(positional[0]); err != nil {
+			return err
+		}
+		if year, err = parseYear(positional[1]); err != nil {
+			return err
+		}
+	default:
+		return usageErrorf("too many arguments")
+	}
+
+	rows := holidayRows(regions, year, month)
+	switch strings.ToLower(cmp.Or(format, "text")) {
+	case "text":
+		return writeHolidayText(stdout, rows, len(regions) > 1)
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+		return writeHolidayCSV(stdout, rows)
+	default:
+		return usageErrorf("invalid format %q, expected text, json or csv", format)
+	}
+}
+
+// holidayRows returns the holidays of the regions that fall or are observed
+// in the year, or in the month if it is not zero, ordered by date and then by
+// region.
+func holidayRows(regions []*calendar.Region, year int, month time.Month) []holidayRow {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	if month != 0 {
+		from = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, 0)
+	}
+	rows := []holidayRow{}
+	for _, r := range regions {
+		// Holidays near the turn of a year can be observed in the year before
+		// or after.
+		for y := year - 1; y <= year+1; y++ {
+			for _, h := range r.Holidays(y) {
+				if !inRange(h.Date, from, to) && !inRange(h.Observed, from, to) {
+					continue
+				}
+				rows = append(rows, holidayRow{
+					Date:     h.Date.Format(time.DateOnly),
+					Weekday:  h.Date.Weekday().String(),
+					Name:     h.Name,
+					Observed: h.Observed.Format(time.DateOnly),
+					Region:   r.Code,
+				})
+			}
+		}
+	}
+	// Dates in ISO 8601 sort as text.
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Date < rows[j].Date })
+	return rows
+}
+
+// writeHolidayText prints the holidays as a table. The observed date is only
+// shown if it differs from the date, and the region only for several regions.
+func writeHolidayText(w io.Writer, rows []holidayRow, withRegion bool) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	header := "DATE\tWEEKDAY\tNAME\tOBSERVED"
+	if withRegion {
+		header += "\tREGION"
+	}
+	fmt.Fprintln(tw, header)
+	for _, row := range rows {
+		observed := row.Observed
+		if observed == row.Date {
+			observed = ""
+		}
+		line := strings.Join([]string{row.Date, row.Weekday[:3], row.Name, observed}, "\t")
+		if withRegion {
+			line += "\t" + row.Region
+		}
+		fmt.Fprintln(tw, line)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// Empty observed dates leave padding at the end of the line.
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line != "" {
+			fmt.Fprintln(w, strings.TrimRight(line, " \n"))
+		}
+	}
+	return nil
+}
+
+// writeHolidayCSV prints the holidays as CSV with a header line.
+func writeHolidayCSV(w io.Writer, rows []holidayRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "weekday", "name", "observed", "region"})
+	for _, row := range rows {
+		cw.Write([]string{row.Date, row.Weekday, row.Name, row.Observed, row.Region})
+	}
+	cw.Flush()
+	return cw.Error()
+}
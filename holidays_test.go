@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mojotx/cal/pkg/calendar"
+	"github.com/stretchr/testify/assert"
+)
+
+// lookupRegions returns the built-in regions with the given codes.
+func lookupRegions(t *testing.T, codes ...string) []*calendar.Region {
+	regions := make([]*calendar.Region, len(codes))
+	for i, code := range codes {
+		r, err := calendar.LookupRegion(code)
+		assert.NoError(t, err)
+		regions[i] = r
+	}
+	return regions
+}
+
+func TestHolidayRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		regions  []string
+		year     int
+		month    time.Month
+		expected []string
+	}{
+		{
+			name:     "month",
+			regions:  []string{"us"},
+			year:     2026,
+			month:    time.July,
+			expected: []string{"2026-07-04 Independence Day (2026-07-03) US"},
+		},
+		{
+			name:    "several regions by date, then in the order given",
+			regions: []string{"de", "us", "gb"},
+			year:    2026,
+			month:   time.December,
+			expected: []string{
+				"2026-12-25 1. Weihnachtstag (2026-12-25) DE",
+				"2026-12-25 Christmas Day (2026-12-25) US",
+				"2026-12-25 Christmas Day (2026-12-25) GB",
+				"2026-12-26 2. Weihnachtstag (2026-12-26) DE",
+				"2026-12-26 Boxing Day (2026-12-28) GB",
+			},
+		},
+		{
+			name:    "observed in the year before",
+			regions: []string{"us"},
+			year:    2021,
+			month:   time.December,
+			expected: []string{
+				"2021-12-25 Christmas Day (2021-12-24) US",
+				"2022-01-01 New Year's Day (2021-12-31) US",
+			},
+		},
+		{
+			name:     "month without holidays",
+			regions:  []string{"us"},
+			year:     2026,
+			month:    time.August,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := holidayRows(lookupRegions(t, tt.regions...), tt.year, tt.month)
+			actual := make([]string, len(rows))
+			for i, row := range rows {
+				actual[i] = row.Date + " " + row.Name + " (" + row.Observed + ") " + row.Region
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestHolidayRowsYear(t *testing.T) {
+	rows := holidayRows(lookupRegions(t, "us", "gb"), 2026, 0)
+	assert.Equal(t, "2026-01-01", rows[0].Date)
+	assert.Equal(t, "2026-12-26", rows[len(rows)-1].Date)
+	for i := 1; i < len(rows); i++ {
+		assert.LessOrEqual(t, rows[i-1].Date, rows[i].Date, "holidays should be ordered by date")
+	}
+}
+
+func TestWriteHolidays(t *testing.T) {
+	rows := []holidayRow{
+		{Date: "2026-12-25", Weekday: "Friday", Name: "Christmas Day", Observed: "2026-12-25", Region: "GB"},
+		{Date: "2026-12-26", Weekday: "Saturday", Name: "Boxing Day", Observed: "2026-12-28", Region: "GB"},
+		{Date: "2026-12-31", Weekday: "Thursday", Name: `Office "party", early close`, Observed: "2026-12-31", Region: "acme"},
+	}
+	tests := []struct {
+		name     string
+		write    func(w *bytes.Buffer) error
+		expected []string
+	}{
+		{
+			name:  "text",
+			write: func(w *bytes.Buffer) error { return writeHolidayText(w, rows[:2], false) },
+			expected: []string{
+				"DATE        WEEKDAY  NAME           OBSERVED",
+				"2026-12-25  Fri      Christmas Day",
+				"2026-12-26  Sat      Boxing Day     2026-12-28",
+			},
+		},
+		{
+			name:  "text with regions",
+			write: func(w *bytes.Buffer) error { return writeHolidayText(w, rows, true) },
+			expected: []string{
+				"DATE        WEEKDAY  NAME                         OBSERVED    REGION",
+				"2026-12-25  Fri      Christmas Day                            GB",
+				"2026-12-26  Sat      Boxing Day                   2026-12-28  GB",
+				`2026-12-31  Thu      Office "party", early close              acme`,
+			},
+		},
+		{
+			name:     "text without holidays",
+			write:    func(w *bytes.Buffer) error { return writeHolidayText(w, nil, false) },
+			expected: []string{"DATE  WEEKDAY  NAME  OBSERVED"},
+		},
+		{
+			name:  "CSV",
+			write: func(w *bytes.Buffer) error { return writeHolidayCSV(w, rows) },
+			expected: []string{
+				"date,weekday,name,observed,region",
+				"2026-12-25,Friday,Christmas Day,2026-12-25,GB",
+				"2026-12-26,Saturday,Boxing Day,2026-12-28,GB",
+				`2026-12-31,Thursday,"Office ""party"", early close",2026-12-31,acme`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, tt.write(&buf))
+			assert.Equal(t, strings.Join(tt.expected, "\n")+"\n", buf.String())
+		})
+	}
+}
+
+func TestRunHolidays(t *testing.T) {
+	setEnv(t)
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name: "text",
+			args: []string{"holidays", "-r", "us", "7", "2026"},
+			expected: []string{
+				"DATE        WEEKDAY  NAME              OBSERVED",
+				"2026-07-04  Sat      Independence Day  2026-07-03",
+			},
+		},
+		{
+			name: "observed in the year before",
+			args: []string{"holidays", "-r", "us", "12", "2021"},
+			expected: []string{
+				"DATE        WEEKDAY  NAME            OBSERVED",
+				"2021-12-25  Sat      Christmas Day   2021-12-24",
+				"2022-01-01  Sat      New Year's Day  2021-12-31",
+			},
+		},
+		{
+			name: "JSON",
+			args: []string{"holidays", "-r", "us", "-f", "json", "7", "2026"},
+			expected: []string{
+				"[",
+				"  {",
+				`    "date": "2026-07-04",`,
+				`    "weekday": "Saturday",`,
+				`    "name": "Independence Day",`,
+				`    "observed": "2026-07-03",`,
+				`    "region": "US"`,
+				"  }",
+				"]",
+			},
+		},
+		{
+			name:     "JSON without holidays",
+			args:     []string{"holidays", "-r", "us", "--format", "JSON", "8", "2026"},
+			expected: []string{"[]"},
+		},
+		{
+			name: "CSV",
+			args: []string{"holidays", "-r", "gb,us", "-f", "csv", "12", "2026"},
+			expected: []string{
+				"date,weekday,name,observed,region",
+				"2026-12-25,Friday,Christmas Day,2026-12-25,GB",
+				"2026-12-25,Friday,Christmas Day,2026-12-25,US",
+				"2026-12-26,Saturday,Boxing Day,2026-12-28,GB",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCal(tt.args...)
+			assert.Equal(t, exitOK, code, stderr)
+			assert.Equal(t, strings.Join(tt.expected, "\n")+"\n", stdout)
+		})
+	}
+
+	code, _, stderr := runCal("holidays", "-r", "us", "-f", "xml")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `cal: invalid format "xml", expected text, json or csv`)
+
+	code, _, stderr = runCal("holidays")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "cal: no holiday region, use --region or set holidays in the config file")
+}
@@ -1,47 +1,651 @@
 package main
 
 import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime/debug"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/mojotx/cal/pkg/calendar"
+	"github.com/mojotx/cal/pkg/ical"
 )
 
+// Exit codes returned by run.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
+// usageError reports a command line that cannot be understood. It makes run
+// exit with exitUsage instead of exitError.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usageErrorf formats a usageError.
+func usageErrorf(format string, a ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+// command is a subcommand such as "cal version".
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer) error
+}
+
+// commands lists the subcommands. It is filled in by init because the help
+// command refers back to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "help", summary: "show this help and exit", run: runHelp},
+		{name: "version", summary: "print version information and exit", run: runVersion},
+		{name: "easter", summary: "print the date of Western or Orthodox Easter Sunday", run: runEaster},
+		{name: "holidays", summary: "list the holidays of a year or a month as a table, JSON or CSV", run: runHolidays},
+		{name: "export", summary: "write holidays, Easter or recurring dates such as sprints as an iCalendar file", run: runExport},
+		{name: "config", summary: "print the effective settings (show) or the config file path (path)", run: runConfig},
+	}
+}
+
+// lookupCommand returns the subcommand with the given name, if any.
+func lookupCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
 func main() {
-	switch len(os.Args) {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	// calendarArgs are the arguments that take the options of the calendar,
+	// such as --color.
+	runFunc, cmdArgs, calendarArgs := runCalendar, args, args
+	if len(args) > 0 {
+		if c, ok := lookupCommand(args[0]); ok {
+			runFunc, cmdArgs, calendarArgs = c.run, args[1:], nil
+			if c.name == "config" && len(cmdArgs) > 0 && cmdArgs[0] == "show" {
+				calendarArgs = cmdArgs[1:]
+			}
+		}
+	}
+	err := runFunc(cmdArgs, stdout)
+
+	var uerr *usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &uerr):
+		printError(stderr, err, errorColor(calendarArgs))
+		fmt.Fprintln(stderr, "Try 'cal --help' for more information.")
+		return exitUsage
+	default:
+		printError(stderr, err, errorColor(calendarArgs))
+		return exitError
+	}
+}
+
+// errorColor returns the color mode for error messages: that of --color in
+// the calendar options args, else that of the config file. It falls back to
+// auto if neither can be read.
+func errorColor(args []string) calendar.ColorMode {
+	var f calendarFlags
+	fs := newCalendarFlagSet(&f)
+	// Options before a parse error are still set.
+	_, _ = fs.parse(args)
+	if cfg, err := loadConfig(configPath()); err == nil {
+		cfg.applyTo(&f, fs)
+	}
+	mode, err := calendar.ParseColorMode(f.color)
+	if err != nil {
+		return calendar.ColorAuto
+	}
+	return mode
+}
+
+// printError writes err to stderr, in red if mode enables color on stderr.
+func printError(stderr io.Writer, err error, mode calendar.ColorMode) {
+	msg := fmt.Sprintf("cal: %s", err)
+	if mode.Enabled(stderr) {
+		red := color.New(color.FgRed)
+		red.EnableColor()
+		msg = red.Sprint(msg)
+	}
+	fmt.Fprintln(stderr, msg)
+}
+
+// calendarFlags holds the options of the default calendar command.
+type calendarFlags struct {
+	help      bool
+	version   bool
+	year      bool
+	monday    bool
+	sunday    bool
+	weekStart string
+	weeks     bool
+	numbering string
+	julian    bool
+	reform    string
+	locale    string
+	today     string
+	three     bool
+	before    int
+	after     int
+	months    int
+	columns   int
+	vertical  bool
+	color     string
+	theme     string
+	outside   bool
+	weekend   string
+	holidays  string
+	events    string
+	legend    bool
+
+	// inlineTheme is a theme written out in the config file.
+	inlineTheme *calendar.Theme
+}
+
+// newCalendarFlagSet registers the options of the calendar command.
+func newCalendarFlagSet(f *calendarFlags) *flagSet {
+	f.reform = calendar.Reform1752.String()
+	f.color = calendar.ColorAuto.String()
+	fs := newFlagSet("cal")
+	fs.boolVar(&f.year, "y", "year", "display the whole year")
+	fs.boolVar(&f.three, "3", "three", "display the previous, current and next month")
+	fs.intVar(&f.before, "B", "before", "N", "display N months before the month")
+	fs.intVar(&f.after, "A", "after", "N", "display N months after the month")
+	fs.intVar(&f.months, "n", "months", "N", "display N months starting with the month")
+	fs.intVar(&f.columns, "c", "columns", "N", "lay out N months side by side (default fits the terminal)")
+	fs.boolVar(&f.vertical, "", "vertical", "show weekdays as rows and weeks as columns, like ncal")
+	fs.boolVar(&f.monday, "M", "monday", "weeks start on Monday")
+	fs.boolVar(&f.sunday, "S", "sunday", "weeks start on Sunday (default)")
+	fs.stringVar(&f.weekStart, "", "week-start", "DAY", "weeks start on DAY, a weekday name or 0-7")
+	fs.boolVar(&f.weeks, "w", "week-numbers", "show ISO 8601 week numbers")
+	fs.stringVar(&f.numbering, "", "week-numbering", "STYLE", "show week numbers in STYLE: iso, us or none")
+	fs.boolVar(&f.julian, "j", "julian", "number days by day of the year (1-366)")
+	fs.stringVar(&f.reform, "", "reform", "WHEN", "switch from the Julian calendar in 1582, 1752 (default), 1918 or never")
+	fs.stringVar(&f.today, "", "today", "DATE", "treat DATE (YYYY-MM-DD) as today (default from CAL_TODAY)")
+	fs.stringVar(&f.locale, "", "locale", "NAME", "name months and weekdays in language NAME (default from LC_ALL, LC_TIME or LANG)")
+	fs.stringVar(&f.color, "", "color", "WHEN", "highlight today: auto (default, on terminals unless NO_COLOR is set), always or never")
+	fs.stringVar(&f.theme, "", "theme", "THEME", "highlight with a built-in theme ("+strings.Join(calendar.Themes(), ", ")+") or a theme file (default from CAL_THEME)")
+	fs.stringVar(&f.weekend, "", "weekend", "DAYS", "highlight DAYS, e.g. fri,sat, as the weekend (default sat,sun; none for no weekend)")
+	fs.stringVar(&f.holidays, "", "holidays", "REGIONS", "highlight the holidays of REGIONS, e.g. us or gb,de ("+strings.Join(calendar.Regions(), ", ")+") or holiday files")
+	fs.boolVar(&f.outside, "", "outside-days", "show the days of adjacent months in empty cells")
+	fs.stringVar(&f.events, "", "events", "FILES", "mark the days with events in the iCalendar (.ics) FILES, separated by commas")
+	fs.boolVar(&f.legend, "", "legend", "list the marked events below the calendar")
+	fs.boolVar(&f.help, "h", "help", "show this help and exit")
+	fs.boolVar(&f.version, "V", "version", "print version information and exit")
+	return fs
+}
+
+// parseCalendarArgs parses the options of the calendar command and completes
+// them from the config file. The config file is not read for --help and
+// --version, so that they work even if it is invalid.
+func parseCalendarArgs(args []string) (*calendarFlags, []string, error) {
+	var f calendarFlags
+	fs := newCalendarFlagSet(&f)
+	positional, err := fs.parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, &usageError{msg: err.Error()}
+	}
+	if f.help || f.version {
+		return &f, positional, nil
+	}
+	if err := completeFromConfig(&f, fs); err != nil {
+		return nil, nil, err
+	}
+	return &f, positional, nil
+}
+
+// completeFromConfig fills in the options of f that the config file sets but
+// the command line parsed by fs does not.
+func completeFromConfig(f *calendarFlags, fs *flagSet) error {
+	cfg, err := loadConfig(configPath())
+	if err != nil {
+		return err
+	}
+	cfg.applyTo(f, fs)
+	return nil
+}
+
+// runCalendar renders the calendar selected by args.
+func runCalendar(args []string, stdout io.Writer) error {
+	f, positional, err := parseCalendarArgs(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return runHelp(nil, stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if f.help {
+		return runHelp(nil, stdout)
+	}
+	if f.version {
+		return runVersion(nil, stdout)
+	}
+
+	now, err := f.todayDate()
+	if err != nil {
+		return err
+	}
+	s, err := f.settings()
+	if err != nil {
+		return err
+	}
+	opts := append(s.options(), calendar.WithToday(now))
+
+	month, year := now.Month(), now.Year()
+	switch len(positional) {
 
 	// No arguments provided
-	case 1:
-		now := time.Now()
-		calendar.DumpMonth(now.Month(), now.Year())
+	case 0:
 
 	// One argument is a year
+	case 1:
+		if year, err = parseYear(positional[0]); err != nil {
+			return err
+		}
+		month = time.January
+		f.year = f.year || !f.spansMonths()
+
+	// Two arguments: month and year
 	case 2:
-		year, err := strconv.ParseInt(os.Args[1], 10, 64)
+		if month, err = parseMonth(positional[0]); err != nil {
+			return err
+		}
+		if year, err = parseYear(positional[1]); err != nil {
+			return err
+		}
+
+	default:
+		return usageErrorf("too many arguments")
+	}
+
+	if err := f.checkSpan(); err != nil {
+		return err
+	}
+	switch {
+	case f.year:
+		opts = append(opts, calendar.WithEvents(eventsIn(s.calendars, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), 12)...))
+		opts = append(opts, calendar.WithMonthsPerRow(f.monthsPerRow(stdout, 12, opts)))
+		return calendar.RenderYear(stdout, year, opts...)
+	case f.spansMonths():
+		offset, count := f.span()
+		start := time.Date(year, month+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		opts = append(opts, calendar.WithEvents(eventsIn(s.calendars, start, count)...))
+		opts = append(opts, calendar.WithMonthsPerRow(f.monthsPerRow(stdout, count, opts)))
+		return calendar.RenderMonths(stdout, start.Month(), start.Year(), count, opts...)
+	default:
+		opts = append(opts, calendar.WithEvents(eventsIn(s.calendars, time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), 1)...))
+		return calendar.RenderMonth(stdout, month, year, opts...)
+	}
+}
+
+// spansMonths reports whether any option selecting several months is set.
+func (f *calendarFlags) spansMonths() bool {
+	return f.three || f.before != 0 || f.after != 0 || f.months != 0
+}
+
+// checkSpan validates the options selecting several months.
+func (f *calendarFlags) checkSpan() error {
+	switch {
+	case f.before < 0 || f.after < 0:
+		return usageErrorf("--before and --after must not be negative")
+	case f.months < 0:
+		return usageErrorf("--months must not be negative")
+	case f.columns < 0:
+		return usageErrorf("--columns must not be negative")
+	case f.year && f.spansMonths():
+		return usageErrorf("--year cannot be combined with --three, --before, --after or --months")
+	}
+	return nil
+}
+
+// defaultMonthsPerRow is used when the width of the output is unknown.
+const defaultMonthsPerRow = 3
+
+// monthsPerRow returns how many of count months to lay out side by side: the
+// --columns option, else as many as fit the width of the terminal stdout is
+// connected to, else as many as fit the COLUMNS environment variable.
+func (f *calendarFlags) monthsPerRow(stdout io.Writer, count int, opts []calendar.Option) int {
+	if f.columns > 0 {
+		return f.columns
+	}
+	width := 0
+	if file, ok := stdout.(*os.File); ok {
+		width = terminalWidth(file)
+	}
+	if width == 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	if width <= 0 {
+		return defaultMonthsPerRow
+	}
+	return calendar.FitMonthsPerRow(width, count, opts...)
+}
+
+// span returns the offset of the first month to display relative to the
+// selected month, and the number of months to display.
+func (f *calendarFlags) span() (offset, count int) {
+	before, after := f.before, f.after
+	if f.three {
+		before++
+		after++
+	}
+	if f.months > 0 {
+		return -before, f.months
+	}
+	return -before, before + 1 + after
+}
+
+// todayDate returns the date to treat as today: the --today option, else the
+// CAL_TODAY environment variable, else the current date.
+func (f *calendarFlags) todayDate() (time.Time, error) {
+	value, source := f.today, "--today"
+	if value == "" {
+		value, source = os.Getenv("CAL_TODAY"), "CAL_TODAY"
+	}
+	if value == "" {
+		return time.Now(), nil
+	}
+	today, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, usageErrorf("invalid date %q in %s, expected YYYY-MM-DD", value, source)
+	}
+	return today, nil
+}
+
+// settings are the calendar settings resolved from the command line, the
+// environment and the config file.
+type settings struct {
+	firstWeekday time.Weekday
+	weekNumbers  calendar.WeekNumbering
+	dayOfYear    bool
+	layout       calendar.Layout
+	reform       calendar.Reform
+	locale       *calendar.Locale
+	color        calendar.ColorMode
+	themeName    string
+	theme        *calendar.Theme
+	weekend      []time.Weekday
+	holidays     []*calendar.Region
+	holidayNames []string
+	calendars    []*ical.Calendar
+	eventFiles   []string
+	legend       bool
+	outsideDays  bool
+	columns      int
+}
+
+// settings resolves the command line flags, which have been completed from
+// the config file, and the environment.
+func (f *calendarFlags) settings() (*settings, error) {
+	s := &settings{
+		firstWeekday: time.Sunday,
+		dayOfYear:    f.julian,
+		outsideDays:  f.outside,
+		legend:       f.legend,
+		columns:      f.columns,
+		weekend:      []time.Weekday{time.Saturday, time.Sunday},
+	}
+
+	switch {
+	case f.monday && f.sunday:
+		return nil, usageErrorf("--monday and --sunday are mutually exclusive")
+	case f.weekStart != "" && (f.monday || f.sunday):
+		return nil, usageErrorf("--week-start cannot be combined with --monday or --sunday")
+	case f.monday:
+		s.firstWeekday = time.Monday
+	case f.weekStart != "":
+		weekday, err := calendar.ParseWeekday(f.weekStart)
 		if err != nil {
-			color.Red("error parsing year: %s", err.Error())
-			os.Exit(1)
+			return nil, &usageError{msg: err.Error()}
 		}
-		calendar.DumpYear(int(year))
+		s.firstWeekday = weekday
+	}
 
-	// Two arguments: month and year
-	case 3:
-		month, err := strconv.ParseInt(os.Args[1], 10, 64)
+	switch {
+	case f.numbering != "":
+		numbering, err := calendar.ParseWeekNumbering(f.numbering)
 		if err != nil {
-			color.Red("error parsing month: %s", err.Error())
-			os.Exit(1)
+			return nil, &usageError{msg: err.Error()}
 		}
-		year, err := strconv.ParseInt(os.Args[2], 10, 64)
+		s.weekNumbers = numbering
+	case f.weeks:
+		s.weekNumbers = calendar.ISOWeekNumbers
+	}
+
+	if f.vertical {
+		s.layout = calendar.LayoutVertical
+	}
+
+	var err error
+	if s.color, err = calendar.ParseColorMode(f.color); err != nil {
+		return nil, &usageError{msg: err.Error()}
+	}
+
+	s.themeName, s.theme = cmp.Or(f.theme, os.Getenv("CAL_THEME")), f.inlineTheme
+	if s.theme == nil {
+		s.themeName = cmp.Or(s.themeName, "default")
+		if s.theme, err = loadTheme(s.themeName); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.weekend != "" {
+		if s.weekend, err = calendar.ParseWeekend(f.weekend); err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+	}
+
+	if f.holidays != "" {
+		if s.holidays, s.holidayNames, err = parseRegions(f.holidays); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.events != "" {
+		if s.calendars, s.eventFiles, err = loadEvents(f.events); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.reform, err = calendar.ParseReform(f.reform); err != nil {
+		return nil, &usageError{msg: err.Error()}
+	}
+
+	if s.locale, err = lookupLocale(f.locale); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// options converts the settings into calendar options.
+func (s *settings) options() []calendar.Option {
+	return []calendar.Option{
+		calendar.WithFirstWeekday(s.firstWeekday),
+		calendar.WithWeekNumbers(s.weekNumbers),
+		calendar.WithDayOfYear(s.dayOfYear),
+		calendar.WithLayout(s.layout),
+		calendar.WithReform(s.reform),
+		calendar.WithLocale(s.locale),
+		calendar.WithColor(s.color),
+		calendar.WithTheme(s.theme),
+		calendar.WithWeekend(s.weekend...),
+		calendar.WithHolidays(s.holidays...),
+		calendar.WithOutsideDays(s.outsideDays),
+		calendar.WithEventLegend(s.legend),
+	}
+}
+
+// lookupLocale returns the locale with the given name, or the locale
+// selected by the environment if name is empty.
+func lookupLocale(name string) (*calendar.Locale, error) {
+	if name == "" {
+		return calendar.LocaleFromEnv(), nil
+	}
+	locale, err := calendar.LookupLocale(name)
+	if err != nil {
+		return nil, &usageError{msg: fmt.Sprintf("%s (supported: %s)", err, strings.Join(calendar.Locales(), ", "))}
+	}
+	return locale, nil
+}
+
+// parseRegions parses a comma-separated list of holiday regions, which are
+// built-in region codes or the paths of holiday files. "none" is the empty
+// list. It also returns the names of the regions, which are lower-case codes
+// or paths.
+func parseRegions(s string) ([]*calendar.Region, []string, error) {
+	if strings.EqualFold(s, "none") {
+		return nil, nil, nil
+	}
+	var regions []*calendar.Region
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		r, err := loadRegion(name)
 		if err != nil {
-			color.Red("error parsing year: %s", err.Error())
-			os.Exit(1)
+			return nil, nil, err
 		}
-		calendar.DumpMonth(time.Month(month), int(year))
+		if _, err := calendar.LookupRegion(name); err == nil {
+			name = strings.ToLower(r.Code)
+		}
+		regions = append(regions, r)
+		names = append(names, name)
+	}
+	return regions, names, nil
+}
 
-	default:
-		color.Red("usage: %s [month] [year]", os.Args[0])
+// loadRegion returns the built-in holiday region with the given code, or else
+// reads the holiday file at that path.
+func loadRegion(name string) (*calendar.Region, error) {
+	r, lookupErr := calendar.LookupRegion(name)
+	if lookupErr == nil {
+		return r, nil
+	}
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) && !strings.ContainsRune(name, filepath.Separator) && filepath.Ext(name) == "" {
+		return nil, &usageError{msg: lookupErr.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r, err = calendar.ParseHolidayFile(name, data); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return r, nil
+}
+
+// loadTheme returns the built-in theme with the given name, or else reads the
+// theme file at that path. An empty name selects the default theme.
+func loadTheme(name string) (*calendar.Theme, error) {
+	if name == "" {
+		return calendar.DefaultTheme, nil
+	}
+	theme, lookupErr := calendar.LookupTheme(name)
+	if lookupErr == nil {
+		return theme, nil
+	}
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) && !strings.ContainsRune(name, filepath.Separator) {
+		return nil, &usageError{msg: lookupErr.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if theme, err = calendar.ParseTheme(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return theme, nil
+}
+
+// parseYear parses a year in the range 1..9999.
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, usageErrorf("invalid year %q", s)
+	}
+	if year < 1 || year > 9999 {
+		return 0, usageErrorf("year %d not in range 1..9999", year)
+	}
+	return year, nil
+}
+
+// parseMonth parses a month given as a number (1..12) or as an English month
+// name, which may be abbreviated to three or more letters.
+func parseMonth(s string) (time.Month, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 || n > 12 {
+			return 0, usageErrorf("month %d not in range 1..12", n)
+		}
+		return time.Month(n), nil
+	}
+	if len(s) >= 3 {
+		for m := time.January; m <= time.December; m++ {
+			if strings.HasPrefix(strings.ToLower(m.String()), strings.ToLower(s)) {
+				return m, nil
+			}
+		}
+	}
+	return 0, usageErrorf("invalid month %q", s)
+}
+
+// runHelp prints the usage message.
+func runHelp(_ []string, stdout io.Writer) error {
+	var f calendarFlags
+	fs := newCalendarFlagSet(&f)
+
+	fmt.Fprintln(stdout, "Usage:")
+	fmt.Fprintln(stdout, "  cal [options] [[month] year]")
+	fmt.Fprintln(stdout, "  cal <command> [arguments]")
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Displays a calendar. Without arguments the current month is shown, with a")
+	fmt.Fprintln(stdout, "year the whole year, and with a month and a year that month.")
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Options:")
+	fs.printDefaults(stdout)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(stdout, "  %-10s  %s\n", c.name, c.summary)
+	}
+	return nil
+}
 
+// runVersion prints the version of the binary.
+func runVersion(_ []string, stdout io.Writer) error {
+	v := version
+	if info, ok := debug.ReadBuildInfo(); ok && v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		v = info.Main.Version
 	}
+	fmt.Fprintf(stdout, "cal %s\n", v)
+	return nil
 }
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setEnv isolates cal from the environment of the test: no config file,
+// English, no color and July 15, 2025 as today.
+func setEnv(t *testing.T) {
+	t.Setenv("CAL_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("CAL_TODAY", "2025-07-15")
+	t.Setenv("CAL_THEME", "")
+	t.Setenv("LC_ALL", "C")
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("COLUMNS", "")
+}
+
+// runCal runs cal with args and returns the exit code and the output.
+func runCal(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunOptions(t *testing.T) {
+	setEnv(t)
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"combined short options", []string{"-wM", "7", "2025"}, []string{"-w", "-M", "7", "2025"}},
+		{"combined with three months", []string{"-3j"}, []string{"-3", "-j"}},
+		{"combined with a value", []string{"-jB", "1"}, []string{"-j", "-B", "1"}},
+		{"attached value", []string{"-A2", "7", "2025"}, []string{"-A", "2", "7", "2025"}},
+		{"combined with an attached value", []string{"-jA2"}, []string{"-j", "-A", "2"}},
+		{"attached value after arguments", []string{"7", "2025", "-B1"}, []string{"-B", "1", "7", "2025"}},
+		{"options after arguments", []string{"7", "2025", "-M"}, []string{"-M", "7", "2025"}},
+		{"options between arguments", []string{"7", "-M", "2025"}, []string{"-M", "7", "2025"}},
+		{"end of options", []string{"-M", "--", "7", "2025"}, []string{"-M", "7", "2025"}},
+		{"value after equals sign", []string{"--locale=de", "-w"}, []string{"--locale", "de", "-w"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCal(tt.args...)
+			assert.Equal(t, exitOK, code, stderr)
+			_, expected, _ := runCal(tt.expected...)
+			assert.Equal(t, expected, stdout)
+		})
+	}
+}
+
+func TestRunCombinedOptions(t *testing.T) {
+	setEnv(t)
+	code, stdout, _ := runCal("-wM", "7", "2025")
+	assert.Equal(t, exitOK, code)
+	lines := strings.Split(stdout, "\n")
+	assert.Equal(t, "   Mo Tu We Th Fr Sa Su", strings.TrimRight(lines[1], " "))
+	assert.Equal(t, "27     1  2  3  4  5  6", strings.TrimRight(lines[2], " "))
+}
+
+func TestRunExitCodes(t *testing.T) {
+	setEnv(t)
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"month", []string{"7", "2025"}, exitOK, ""},
+		{"help", []string{"--help"}, exitOK, ""},
+		{"unknown option", []string{"-x"}, exitUsage, "cal: flag provided but not defined: -x"},
+		{"unknown combined option", []string{"-wX"}, exitUsage, "cal: flag provided but not defined: -wX"},
+		{"missing value", []string{"--locale"}, exitUsage, "cal: flag needs an argument: -locale"},
+		{"too many arguments", []string{"1", "2", "3"}, exitUsage, "cal: too many arguments"},
+		{"invalid month", []string{"13", "2025"}, exitUsage, "cal: month 13 not in range 1..12"},
+		{"argument after end of options", []string{"--", "-1"}, exitUsage, "cal: year -1 not in range 1..9999"},
+		{"missing holiday file", []string{"--holidays", "missing.yaml"}, exitError, "cal: open missing.yaml: no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCal(tt.args...)
+			assert.Equal(t, tt.code, code)
+			if tt.stderr == "" {
+				assert.Empty(t, stderr)
+				return
+			}
+			assert.Equal(t, tt.stderr, strings.SplitN(stderr, "\n", 2)[0])
+			if tt.code == exitUsage {
+				assert.Contains(t, stderr, "Try 'cal --help' for more information.")
+			}
+		})
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CAL_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	assert.Equal(t, filepath.Join(home, ".config", "cal", "config.yaml"), configPath())
+
+	t.Setenv("XDG_CONFIG_HOME", "/etc/xdg")
+	assert.Equal(t, filepath.Join("/etc/xdg", "cal", "config.yaml"), configPath())
+
+	t.Setenv("CAL_CONFIG", "/tmp/cal.yaml")
+	assert.Equal(t, "/tmp/cal.yaml", configPath())
+}
+
+// writeConfig writes a config file with the given lines and points
+// CAL_CONFIG to it.
+func writeConfig(t *testing.T, lines ...string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644))
+	t.Setenv("CAL_CONFIG", path)
+	return path
+}
+
+func TestRunConfigPrecedence(t *testing.T) {
+	setEnv(t)
+	writeConfig(t, "week-start: monday", "locale: de", "week-numbers: iso")
+
+	tests := []struct {
+		name   string
+		args   []string
+		header string
+	}{
+		{"config file", []string{"7", "2025"}, "   Mo Di Mi Do Fr Sa So"},
+		{"option overrides config file", []string{"-S", "7", "2025"}, "   So Mo Di Mi Do Fr Sa"},
+		{"options override config file", []string{"--locale", "en", "--week-numbering", "none", "7", "2025"}, "Mo Tu We Th Fr Sa Su"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCal(tt.args...)
+			assert.Equal(t, exitOK, code, stderr)
+			assert.Equal(t, tt.header, strings.TrimRight(strings.Split(stdout, "\n")[1], " "))
+		})
+	}
+}
+
+func TestRunConfigShow(t *testing.T) {
+	setEnv(t)
+	path := writeConfig(t, "week-start: monday", "locale: de", "columns: 2", "holidays: us")
+
+	code, stdout, stderr := runCal("config", "show", "--locale", "en", "--holidays", "none")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, strings.Join([]string{
+		"# config file: " + path,
+		"week-start: monday",
+		"week-numbers: none",
+		"locale: en",
+		`reform: "1752"`,
+		"color: auto",
+		"theme: default",
+		"weekend: sat,sun",
+		"holidays: none",
+		"layout: horizontal",
+		"columns: 2",
+		"outside-days: false",
+		"event-legend: false",
+		"",
+	}, "\n"), stdout)
+
+	code, stdout, _ = runCal("config", "path")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, path+"\n", stdout)
+
+	for _, args := range [][]string{{"config", "show", "--help"}, {"config", "show", "-h"}, {"config", "--help"}} {
+		code, stdout, _ = runCal(args...)
+		assert.Equal(t, exitOK, code)
+		assert.True(t, strings.HasPrefix(stdout, "Usage:\n  cal config show [options]\n"), "cal %s: %s", strings.Join(args, " "), stdout)
+		assert.NotContains(t, stdout, "# config file")
+	}
+}
+
+func TestRunConfigErrors(t *testing.T) {
+	setEnv(t)
+	path := writeConfig(t, "week-start: someday")
+	code, _, stderr := runCal("7", "2025")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "cal: "+path+`: week-start: invalid weekday "someday"`+"\n", stderr)
+
+	writeConfig(t, "weekstart: monday")
+	code, _, stderr = runCal("7", "2025")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "field weekstart not found")
+
+	// Help and version do not need the config file.
+	writeConfig(t, "weekstart: mon")
+	for _, args := range [][]string{{"--help"}, {"-h"}, {"--version"}, {"-V"}, {"config", "show", "--help"}} {
+		code, stdout, stderr := runCal(args...)
+		assert.Equal(t, exitOK, code, "cal %s: %s", strings.Join(args, " "), stderr)
+		assert.NotEmpty(t, stdout)
+	}
+
+	code, _, stderr = runCal("config", "list")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `cal: unknown config command "list", expected show or path`)
+}
+
+func TestRunEaster(t *testing.T) {
+	setEnv(t)
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"Gregorian", []string{"easter", "2026"}, "April 5, 2026"},
+		{"Orthodox", []string{"easter", "-o", "2026"}, "April 12, 2026"},
+		{"Julian before the reform", []string{"easter", "1500"}, "April 19, 1500"},
+		{"Orthodox before the reform", []string{"easter", "-o", "1500"}, "April 19, 1500"},
+		{"proleptic Gregorian", []string{"easter", "--reform", "never", "1500"}, "April 1, 1500"},
+		{"Catholic reform", []string{"easter", "--reform", "1582", "1700"}, "April 11, 1700"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCal(tt.args...)
+			assert.Equal(t, exitOK, code, stderr)
+			assert.Equal(t, tt.expected+"\n", stdout)
+		})
+	}
+
+	writeConfig(t, "reform: never")
+	_, stdout, _ := runCal("easter", "1500")
+	assert.Equal(t, "April 1, 1500\n", stdout)
+}
+
+func TestRunConfigOverriddenFiles(t *testing.T) {
+	setEnv(t)
+	path := writeConfig(t, "holidays: missing.yaml")
+	missing := filepath.Join(filepath.Dir(path), "missing.yaml")
+	events := filepath.Join(t.TempDir(), "other.ics")
+	assert.NoError(t, os.WriteFile(events, []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n"), 0o644))
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"holidays option", []string{"--holidays", "us", "7", "2025"}},
+		{"no holidays", []string{"--holidays", "none", "7", "2025"}},
+		{"holidays command", []string{"holidays", "-r", "us", "2025"}},
+		{"easter", []string{"easter", "2026"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCal(tt.args...)
+			assert.Equal(t, exitOK, code, stderr)
+		})
+	}
+
+	code, _, stderr := runCal("7", "2025")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "cal: open "+missing+": no such file or directory\n", stderr)
+
+	path = writeConfig(t, "events: missing.ics")
+	missing = filepath.Join(filepath.Dir(path), "missing.ics")
+	for _, args := range [][]string{{"--events", events, "7", "2025"}, {"easter", "2026"}, {"holidays", "-r", "us", "2025"}} {
+		code, _, stderr := runCal(args...)
+		assert.Equal(t, exitOK, code, "cal %s: %s", strings.Join(args, " "), stderr)
+	}
+	code, _, stderr = runCal("7", "2025")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "cal: open "+missing+": no such file or directory\n", stderr)
+
+	writeConfig(t, "holidays: us,")
+	code, _, stderr = runCal("easter", "2026")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, `holidays: empty name in "us,"`)
+}
+
+func TestRunErrorColor(t *testing.T) {
+	setEnv(t)
+	red := "\x1b[31mcal: month 13 not in range 1..12\x1b[0m"
+	tests := []struct {
+		name   string
+		config []string
+		args   []string
+		red    bool
+	}{
+		{name: "auto", args: []string{"13", "2025"}},
+		{name: "option", args: []string{"--color", "always", "13", "2025"}, red: true},
+		{name: "option after the error", args: []string{"13", "2025", "--color=always"}, red: true},
+		{name: "config file", config: []string{"color: always"}, args: []string{"13", "2025"}, red: true},
+		{name: "option overrides config file", config: []string{"color: always"}, args: []string{"--color", "never", "13", "2025"}},
+		{name: "config file for commands", config: []string{"color: always"}, args: []string{"holidays", "-r", "us", "13", "2025"}, red: true},
+		{name: "config show", args: []string{"config", "show", "--color", "always", "--locale", "xx"}, red: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config != nil {
+				writeConfig(t, tt.config...)
+			}
+			code, _, stderr := runCal(tt.args...)
+			assert.NotEqual(t, exitOK, code)
+			if tt.red {
+				assert.Contains(t, stderr, "\x1b[31mcal: ")
+			} else {
+				assert.NotContains(t, stderr, "\x1b[")
+			}
+		})
+	}
+	_, _, stderr := runCal("--color", "always", "13", "2025")
+	assert.Equal(t, red, strings.SplitN(stderr, "\n", 2)[0])
+}
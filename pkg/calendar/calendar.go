@@ -4,21 +4,22 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"regexp"
 	"strings"
 	"time"
-	"unicode/utf8"
 
-	"github.com/fatih/color"
 	"github.com/pkg/errors"
 )
 
-// NCenter centers a string in a buffer with a specified width.
+// NCenter centers a string in a buffer with a specified width, measured in
+// terminal columns.
 func NCenter(width int, s string) *bytes.Buffer {
 	const space = "\u0020"
 	var b bytes.Buffer
-	strLen := utf8.RuneCountInString(s)
+	strLen := DisplayWidth(s)
 	totalPad := width - strLen
 	if totalPad < 1 {
 		fmt.Fprint(&b, s)
@@ -30,45 +31,133 @@ func NCenter(width int, s string) *bytes.Buffer {
 	return &b
 }
 
+// weekdayHeader returns the column headings of m, right-aligned in cells of
+// the width of a day.
+func weekdayHeader(m *Month, o *options) string {
+	names := make([]string, 0, 7)
+	for _, weekday := range m.Weekdays() {
+		names = append(names, PadLeft(o.weekdayLabel(weekday), o.cellWidth()))
+	}
+	return strings.Join(names, "\u0020")
+}
+
+// weekdayLabel returns the highlighted abbreviation of weekday.
+func (o *options) weekdayLabel(weekday time.Weekday) string {
+	style := o.theme.Weekday
+	if o.weekend[weekday] {
+		style = o.theme.Weekend
+	}
+	return o.paint(style, o.locale.WeekdayAbbreviation(weekday))
+}
+
+// weekdayColumn returns the zero-based column of weekday in a week that starts
+// on first.
+func weekdayColumn(weekday, first time.Weekday) int {
+	return (int(weekday) - int(first) + 7) % 7
+}
+
+// formatDay returns the number shown for day, right-aligned in a cell and
+// highlighted as the theme says.
+func formatDay(day *Day, o *options) string {
+	number := day.Day
+	if o.dayOfYear {
+		number = day.YearDay
+	}
+	dayStr := fmt.Sprintf("%*d", o.cellWidth(), number)
+	switch {
+	case day.Outside:
+		return o.paint(o.theme.Outside, dayStr)
+	case day.Today:
+		return o.paint(o.theme.Today, dayStr)
+	case day.Holiday:
+		return o.paint(o.theme.Holiday, dayStr)
+	case len(day.Events) > 0:
+		return o.paint(o.theme.Event, dayStr)
+	case day.Weekend:
+		return o.paint(o.theme.Weekend, dayStr)
+	}
+	return dayStr
+}
+
 // buildMonthCalendar generates a calendar for a specific month and year.
-func buildMonthCalendar(month time.Month, year int) string {
-	title := fmt.Sprintf("%s %d", month, year)
-	b := NCenter(20, title)
-	b.WriteRune('\n')
-	b.WriteString("Su Mo Tu We Th Fr Sa\n")
+func buildMonthCalendar(month time.Month, year int, opts ...Option) string {
+	o := newOptions(opts)
+	return buildMonth(newMonth(month, year, o), o)
+}
 
-	firstDayThisMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	firstDayNextMonth := firstDayThisMonth.AddDate(0, 1, 0)
-	lastDayThisMonth := firstDayNextMonth.AddDate(0, 0, -1)
+// buildMonth generates the calendar of m.
+func buildMonth(m *Month, o *options) string {
+	b := bytes.NewBufferString(strings.Repeat("\u0020", o.weekNumberWidth()))
+	b.Write(NCenter(o.daysWidth(), o.paint(o.theme.Title, o.locale.monthTitle(m))).Bytes())
+	b.WriteRune('\n')
+	writeMonthGrid(b, m, o)
+	return b.String()
+}
 
-	weekday := firstDayThisMonth.Weekday()
-	Spacer(b, weekday)
+// writeMonthGrid writes the weekday header and the weeks of m to b.
+func writeMonthGrid(b *bytes.Buffer, m *Month, o *options) {
+	b.WriteString(strings.Repeat("\u0020", o.weekNumberWidth()))
+	b.WriteString(weekdayHeader(m, o))
+	b.WriteRune('\n')
 
-	now := time.Now()
-	todayYear, todayMonth, todayDay := now.Date()
+	for _, week := range m.Weeks {
+		if o.weekNumbers != NoWeekNumbers {
+			fmt.Fprintf(b, "%2d ", week.Number)
+		}
+		blanks := 0
+		for column, day := range week.Days {
+			if day == nil {
+				blanks++
+				continue
+			}
+			padCells(b, blanks, o.cellWidth())
+			blanks = 0
 
-	for day := firstDayThisMonth; day.Before(lastDayThisMonth) || day.Equal(lastDayThisMonth); day = day.AddDate(0, 0, 1) {
-		dayStr := fmt.Sprintf("%2d", day.Day())
-		if day.Year() == todayYear && day.Month() == todayMonth && day.Day() == todayDay {
-			dayStr = color.New(color.BgWhite, color.FgBlack).Sprint(dayStr)
+			b.WriteString(formatDay(day, o) + o.dayGap(day))
+			if column == 6 {
+				b.WriteRune('\n')
+			}
 		}
-		fmt.Fprintf(b, "%s ", dayStr)
-		if day.Weekday() == time.Saturday {
+	}
+	b.WriteRune('\n')
+}
+
+// RenderMonth writes the calendar for a specific month and year to w.
+func RenderMonth(w io.Writer, month time.Month, year int, opts ...Option) error {
+	o := newWriterOptions(w, opts)
+	m := newMonth(month, year, o)
+	var b bytes.Buffer
+	if o.layout == LayoutVertical {
+		b.WriteString(buildVerticalMonth(m, o))
+	} else {
+		b.WriteString(buildMonth(m, o))
+	}
+	var legend bytes.Buffer
+	writeEventLegend(&legend, []*Month{m}, o)
+	if legend.Len() > 0 {
+		// Months of fewer than six weeks already end with a blank line.
+		if !bytes.HasSuffix(b.Bytes(), []byte("\n\n")) {
 			b.WriteRune('\n')
 		}
+		b.Write(legend.Bytes())
 	}
-	b.WriteRune('\n')
-	return b.String()
+	_, err := w.Write(b.Bytes())
+	return errors.Wrap(err, "error writing month calendar")
 }
 
 // DumpMonth prints the calendar for a specific month and year.
-func DumpMonth(month time.Month, year int) {
-	fmt.Print(buildMonthCalendar(month, year))
+func DumpMonth(month time.Month, year int, opts ...Option) {
+	_ = RenderMonth(os.Stdout, month, year, opts...)
 }
 
 // DumpMonthToSlice returns the calendar for a specific month and year as a slice of strings.
-func DumpMonthToSlice(month time.Month, year int) []string {
-	calStr := buildMonthCalendar(month, year)
+func DumpMonthToSlice(month time.Month, year int, opts ...Option) []string {
+	return splitLines(buildMonthCalendar(month, year, opts...))
+}
+
+// splitLines splits a rendered calendar into lines, dropping trailing
+// whitespace and empty lines.
+func splitLines(calStr string) []string {
 	var lineSlice []string
 	scanner := bufio.NewScanner(strings.NewReader(calStr))
 	for scanner.Scan() {
@@ -80,45 +169,94 @@ func DumpMonthToSlice(month time.Month, year int) []string {
 	return lineSlice
 }
 
-// Spacer writes leading spaces to the buffer based on the weekday.
+// Spacer writes leading spaces to the buffer based on the weekday, for a week
+// that starts on Sunday.
 func Spacer(b *bytes.Buffer, weekday time.Weekday) {
-	for n := int(weekday); n > 0; n-- {
-		b.WriteString("\u0020\u0020\u0020")
-	}
+	padCells(b, int(weekday), 2)
 }
 
-// dumpThreeMonths is a helper that prints three months in a row.
-func dumpThreeMonths(year int, months ...time.Month) error {
-	if len(months) != 3 {
-		return errors.New("dumpThreeMonths requires exactly three months")
-	}
+// padCells writes the blank space taken up by n day cells of the given width,
+// including their separating spaces.
+func padCells(b *bytes.Buffer, n, width int) {
+	b.WriteString(strings.Repeat("\u0020", n*(width+1)))
+}
 
-	monthStrings := make(map[time.Month][]string)
+// yearMonth identifies a month of a particular year.
+type yearMonth struct {
+	year  int
+	month time.Month
+}
+
+// addMonths returns the month n months after ym, crossing year boundaries.
+func (ym yearMonth) addMonths(n int) yearMonth {
+	index := ym.year*12 + int(ym.month) - 1 + n
+	return yearMonth{year: floorDiv(index, 12), month: time.Month(floorMod(index, 12) + 1)}
+}
 
-	for i := months[0]; i <= months[2]; i++ {
-		month := DumpMonthToSlice(i, year)
-		monthStrings[i] = month
+// writeMonthRow is a helper that writes the lines of months side by side to
+// b, padding every month to width columns.
+func writeMonthRow(b *bytes.Buffer, monthStrings [][]string, width int) error {
+	if len(monthStrings) == 0 {
+		return errors.New("writeMonthRow requires at least one month")
 	}
 
-	maxSliceLen := GetMaxSliceLen(monthStrings[months[0]], monthStrings[months[1]], monthStrings[months[2]])
+	maxSliceLen := GetMaxSliceLen(monthStrings...)
 
 	for i := range maxSliceLen {
-		for _, month := range months {
+		for _, lines := range monthStrings {
 			// Check for a line for this month
 			var subString string
-			if i <= len(monthStrings[month])-1 {
-				subString = monthStrings[month][i]
+			if i <= len(lines)-1 {
+				subString = lines[i]
 			} else {
-				subString = strings.Repeat(" ", 20) // Empty space for alignment
+				subString = strings.Repeat(" ", width) // Empty space for alignment
 			}
-			fmt.Printf("%-20s    ", subString)
+			// A marker after the last day may take up a column of the gap.
+			b.WriteString(PadRight(subString, width+monthGap))
 		}
-		fmt.Print("\n")
+		b.WriteRune('\n')
 	}
-	fmt.Print("\n")
+	b.WriteRune('\n')
 	return nil
 }
 
+// RenderMonths writes count consecutive months, starting with the given month
+// and year, to w. The months are laid out side by side, three to a row unless
+// WithMonthsPerRow says otherwise, and may span several years.
+func RenderMonths(w io.Writer, month time.Month, year, count int, opts ...Option) error {
+	if count < 1 {
+		return errors.Errorf("cannot render %d months", count)
+	}
+	start := yearMonth{year: year, month: month}
+	o := newWriterOptions(w, opts)
+
+	var b bytes.Buffer
+	var months []*Month
+	for first := 0; first < count; first += o.monthsPerRow {
+		row := make([]*Month, 0, o.monthsPerRow)
+		for i := first; i < count && i < first+o.monthsPerRow; i++ {
+			ym := start.addMonths(i)
+			row = append(row, newMonth(ym.month, ym.year, o))
+		}
+		months = append(months, row...)
+		if o.layout == LayoutVertical {
+			writeVerticalMonths(&b, row, verticalWeeks, o.locale.monthTitle, o)
+			b.WriteRune('\n')
+			continue
+		}
+		monthStrings := make([][]string, len(row))
+		for i, m := range row {
+			monthStrings[i] = splitLines(buildMonth(m, o))
+		}
+		if err := writeMonthRow(&b, monthStrings, o.monthWidth()); err != nil {
+			return err
+		}
+	}
+	writeEventLegend(&b, months, o)
+	_, err := w.Write(b.Bytes())
+	return errors.Wrap(err, "error writing calendar")
+}
+
 // GetMaxSliceLen returns the maximum length of the provided slices.
 func GetMaxSliceLen(slices ...[]string) int {
 	max := math.MinInt
@@ -131,11 +269,8 @@ func GetMaxSliceLen(slices ...[]string) int {
 }
 
 // DumpYear prints the calendar for an entire year.
-func DumpYear(year int) {
-	_ = dumpThreeMonths(year, time.January, time.February, time.March)
-	_ = dumpThreeMonths(year, time.April, time.May, time.June)
-	_ = dumpThreeMonths(year, time.July, time.August, time.September)
-	_ = dumpThreeMonths(year, time.October, time.November, time.December)
+func DumpYear(year int, opts ...Option) {
+	_ = RenderYear(os.Stdout, year, opts...)
 }
 
 // Helper function to strip ANSI color codes for testing
@@ -2,8 +2,10 @@ package calendar
 
 import (
 	"bytes"
+	"errors"
 	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -53,6 +55,18 @@ func TestNCenter(t *testing.T) {
 			input:    "ab",
 			expected: "  ab   ",
 		},
+		{
+			name:     "double-width characters",
+			width:    20,
+			input:    "2024年3月",
+			expected: "     2024年3月      ",
+		},
+		{
+			name:     "combining marks",
+			width:    10,
+			input:    "fe\u0301vrier",
+			expected: " fe\u0301vrier  ",
+		},
 	}
 
 	for _, tt := range tests {
@@ -67,6 +81,7 @@ func TestBuildMonthCalendar(t *testing.T) {
 		name     string
 		month    time.Month
 		year     int
+		opts     []Option
 		expected string
 	}{
 		{
@@ -93,11 +108,77 @@ func TestBuildMonthCalendar(t *testing.T) {
 				"18 19 20 21 22 23 24 \n" +
 				"25 26 27 28 29 \n",
 		},
+		{
+			name:  "July 2025 starting on Monday",
+			month: time.July,
+			year:  2025,
+			opts:  []Option{WithFirstWeekday(time.Monday)},
+			expected: "     July 2025      \n" +
+				"Mo Tu We Th Fr Sa Su\n" +
+				"    1  2  3  4  5  6 \n" +
+				" 7  8  9 10 11 12 13 \n" +
+				"14 15 16 17 18 19 20 \n" +
+				"21 22 23 24 25 26 27 \n" +
+				"28 29 30 31 \n",
+		},
+		{
+			name:  "June 2025 starting on Saturday",
+			month: time.June,
+			year:  2025,
+			opts:  []Option{WithFirstWeekday(time.Saturday)},
+			expected: "     June 2025      \n" +
+				"Sa Su Mo Tu We Th Fr\n" +
+				"    1  2  3  4  5  6 \n" +
+				" 7  8  9 10 11 12 13 \n" +
+				"14 15 16 17 18 19 20 \n" +
+				"21 22 23 24 25 26 27 \n" +
+				"28 29 30 \n",
+		},
+		{
+			name:  "January 2021 with ISO week numbers",
+			month: time.January,
+			year:  2021,
+			opts:  []Option{WithFirstWeekday(time.Monday), WithWeekNumbers(ISOWeekNumbers)},
+			expected: "       January 2021    \n" +
+				"   Mo Tu We Th Fr Sa Su\n" +
+				"53              1  2  3 \n" +
+				" 1  4  5  6  7  8  9 10 \n" +
+				" 2 11 12 13 14 15 16 17 \n" +
+				" 3 18 19 20 21 22 23 24 \n" +
+				" 4 25 26 27 28 29 30 31 \n\n",
+		},
+		{
+			name:  "December 2024 with US week numbers",
+			month: time.December,
+			year:  2024,
+			opts:  []Option{WithWeekNumbers(USWeekNumbers)},
+			expected: "      December 2024    \n" +
+				"   Su Mo Tu We Th Fr Sa\n" +
+				"49  1  2  3  4  5  6  7 \n" +
+				"50  8  9 10 11 12 13 14 \n" +
+				"51 15 16 17 18 19 20 21 \n" +
+				"52 22 23 24 25 26 27 28 \n" +
+				"53 29 30 31 \n",
+		},
+		{
+			name:  "March 2024 with days of the year",
+			month: time.March,
+			year:  2024,
+			opts:  []Option{WithDayOfYear(true)},
+			expected: "        March 2024         \n" +
+				" Su  Mo  Tu  We  Th  Fr  Sa\n" +
+				"                     61  62 \n" +
+				" 63  64  65  66  67  68  69 \n" +
+				" 70  71  72  73  74  75  76 \n" +
+				" 77  78  79  80  81  82  83 \n" +
+				" 84  85  86  87  88  89  90 \n" +
+				" 91 \n",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildMonthCalendar(tt.month, tt.year)
+			result := buildMonthCalendar(tt.month, tt.year, tt.opts...)
 
 			// Remove any ANSI color codes for comparison
 			result = stripAnsiCodes(result)
@@ -343,18 +424,19 @@ func TestGetMaxSliceLen(t *testing.T) {
 		})
 	}
 }
-func TestDumpThreeMonths(t *testing.T) {
+func TestWriteMonthRow(t *testing.T) {
 	tests := []struct {
 		name     string
 		year     int
 		months   []time.Month
+		opts     []Option
 		wantErr  bool
 		expected string
 	}{
 		{
-			name:    "wrong number of months",
+			name:    "no months",
 			year:    2023,
-			months:  []time.Month{time.January, time.February},
+			months:  []time.Month{},
 			wantErr: true,
 		},
 		{
@@ -382,23 +464,57 @@ func TestDumpThreeMonths(t *testing.T) {
 				"23 24 25 26 27 28 29    28 29 30 31             25 26 27 28 29 30       \n" +
 				"30                                                                      \n\n",
 		},
+		{
+			name:   "first quarter with ISO week numbers",
+			year:   2021,
+			months: []time.Month{time.January, time.February, time.March},
+			opts:   []Option{WithFirstWeekday(time.Monday), WithWeekNumbers(ISOWeekNumbers)},
+			expected: "       January 2021              February 2021                March 2021         \n" +
+				"   Mo Tu We Th Fr Sa Su       Mo Tu We Th Fr Sa Su       Mo Tu We Th Fr Sa Su    \n" +
+				"53              1  2  3     5  1  2  3  4  5  6  7     9  1  2  3  4  5  6  7    \n" +
+				" 1  4  5  6  7  8  9 10     6  8  9 10 11 12 13 14    10  8  9 10 11 12 13 14    \n" +
+				" 2 11 12 13 14 15 16 17     7 15 16 17 18 19 20 21    11 15 16 17 18 19 20 21    \n" +
+				" 3 18 19 20 21 22 23 24     8 22 23 24 25 26 27 28    12 22 23 24 25 26 27 28    \n" +
+				" 4 25 26 27 28 29 30 31                               13 29 30 31                \n\n",
+		},
+		{
+			name:   "fourth quarter with days of the year and week numbers",
+			year:   2023,
+			months: []time.Month{time.October, time.November, time.December},
+			opts:   []Option{WithDayOfYear(true), WithWeekNumbers(ISOWeekNumbers)},
+			expected: "          October 2023                      November 2023                     December 2023           \n" +
+				"    Su  Mo  Tu  We  Th  Fr  Sa        Su  Mo  Tu  We  Th  Fr  Sa        Su  Mo  Tu  We  Th  Fr  Sa    \n" +
+				"40 274 275 276 277 278 279 280    44             305 306 307 308    48                     335 336    \n" +
+				"41 281 282 283 284 285 286 287    45 309 310 311 312 313 314 315    49 337 338 339 340 341 342 343    \n" +
+				"42 288 289 290 291 292 293 294    46 316 317 318 319 320 321 322    50 344 345 346 347 348 349 350    \n" +
+				"43 295 296 297 298 299 300 301    47 323 324 325 326 327 328 329    51 351 352 353 354 355 356 357    \n" +
+				"44 302 303 304                    48 330 331 332 333 334            52 358 359 360 361 362 363 364    \n" +
+				"                                                                     1 365                            \n\n",
+		},
+		{
+			name:   "first quarter in Japanese",
+			year:   2024,
+			months: []time.Month{time.January, time.February, time.March},
+			opts:   []Option{WithLocale(locales["ja"])},
+			expected: "     2024年1月               2024年2月               2024年3月          \n" +
+				"日 月 火 水 木 金 土    日 月 火 水 木 金 土    日 月 火 水 木 金 土    \n" +
+				"    1  2  3  4  5  6                 1  2  3                    1  2    \n" +
+				" 7  8  9 10 11 12 13     4  5  6  7  8  9 10     3  4  5  6  7  8  9    \n" +
+				"14 15 16 17 18 19 20    11 12 13 14 15 16 17    10 11 12 13 14 15 16    \n" +
+				"21 22 23 24 25 26 27    18 19 20 21 22 23 24    17 18 19 20 21 22 23    \n" +
+				"28 29 30 31             25 26 27 28 29          24 25 26 27 28 29 30    \n" +
+				"                                                31                      \n\n",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			stdout := os.Stdout
-			r, w, _ := os.Pipe()
-			os.Stdout = w
-
-			err := dumpThreeMonths(tt.year, tt.months...)
-
-			w.Close()
-			os.Stdout = stdout
-			if _, readErr := buf.ReadFrom(r); readErr != nil {
-				t.Fatalf("Failed to read from pipe: %v", readErr)
+			monthStrings := make([][]string, 0, len(tt.months))
+			for _, month := range tt.months {
+				monthStrings = append(monthStrings, DumpMonthToSlice(month, tt.year, tt.opts...))
 			}
-
+			err := writeMonthRow(&buf, monthStrings, newOptions(tt.opts).monthWidth())
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -419,14 +535,15 @@ func TestDumpYear(t *testing.T) {
 		{
 			name: "test year 2023",
 			year: 2023,
-			expected: "    January 2023           February 2023             March 2023         \n" +
+			expected: "                                2023\n\n" +
+				"      January                 February                 March            \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				" 1  2  3  4  5  6  7              1  2  3  4              1  2  3  4    \n" +
 				" 8  9 10 11 12 13 14     5  6  7  8  9 10 11     5  6  7  8  9 10 11    \n" +
 				"15 16 17 18 19 20 21    12 13 14 15 16 17 18    12 13 14 15 16 17 18    \n" +
 				"22 23 24 25 26 27 28    19 20 21 22 23 24 25    19 20 21 22 23 24 25    \n" +
 				"29 30 31                26 27 28                26 27 28 29 30 31       \n\n" +
-				"     April 2023               May 2023               June 2023          \n" +
+				"       April                    May                     June            \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				"                   1        1  2  3  4  5  6                 1  2  3    \n" +
 				" 2  3  4  5  6  7  8     7  8  9 10 11 12 13     4  5  6  7  8  9 10    \n" +
@@ -434,7 +551,7 @@ func TestDumpYear(t *testing.T) {
 				"16 17 18 19 20 21 22    21 22 23 24 25 26 27    18 19 20 21 22 23 24    \n" +
 				"23 24 25 26 27 28 29    28 29 30 31             25 26 27 28 29 30       \n" +
 				"30                                                                      \n\n" +
-				"     July 2023              August 2023            September 2023       \n" +
+				"        July                   August                September          \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				"                   1           1  2  3  4  5                    1  2    \n" +
 				" 2  3  4  5  6  7  8     6  7  8  9 10 11 12     3  4  5  6  7  8  9    \n" +
@@ -442,7 +559,7 @@ func TestDumpYear(t *testing.T) {
 				"16 17 18 19 20 21 22    20 21 22 23 24 25 26    17 18 19 20 21 22 23    \n" +
 				"23 24 25 26 27 28 29    27 28 29 30 31          24 25 26 27 28 29 30    \n" +
 				"30 31                                                                   \n\n" +
-				"    October 2023           November 2023           December 2023        \n" +
+				"      October                 November                December          \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				" 1  2  3  4  5  6  7              1  2  3  4                    1  2    \n" +
 				" 8  9 10 11 12 13 14     5  6  7  8  9 10 11     3  4  5  6  7  8  9    \n" +
@@ -454,7 +571,8 @@ func TestDumpYear(t *testing.T) {
 		{
 			name: "test year 2024 (leap Year)",
 			year: 2024,
-			expected: "    January 2024           February 2024             March 2024         \n" +
+			expected: "                                2024\n\n" +
+				"      January                 February                 March            \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				"    1  2  3  4  5  6                 1  2  3                    1  2    \n" +
 				" 7  8  9 10 11 12 13     4  5  6  7  8  9 10     3  4  5  6  7  8  9    \n" +
@@ -462,7 +580,7 @@ func TestDumpYear(t *testing.T) {
 				"21 22 23 24 25 26 27    18 19 20 21 22 23 24    17 18 19 20 21 22 23    \n" +
 				"28 29 30 31             25 26 27 28 29          24 25 26 27 28 29 30    \n" +
 				"                                                31                      \n\n" +
-				"     April 2024               May 2024               June 2024          \n" +
+				"       April                    May                     June            \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				"    1  2  3  4  5  6              1  2  3  4                       1    \n" +
 				" 7  8  9 10 11 12 13     5  6  7  8  9 10 11     2  3  4  5  6  7  8    \n" +
@@ -470,14 +588,14 @@ func TestDumpYear(t *testing.T) {
 				"21 22 23 24 25 26 27    19 20 21 22 23 24 25    16 17 18 19 20 21 22    \n" +
 				"28 29 30                26 27 28 29 30 31       23 24 25 26 27 28 29    \n" +
 				"                                                30                      \n\n" +
-				"     July 2024              August 2024            September 2024       \n" +
+				"        July                   August                September          \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				"    1  2  3  4  5  6                 1  2  3     1  2  3  4  5  6  7    \n" +
 				" 7  8  9 10 11 12 13     4  5  6  7  8  9 10     8  9 10 11 12 13 14    \n" +
 				"14 15 16 17 18 19 20    11 12 13 14 15 16 17    15 16 17 18 19 20 21    \n" +
 				"21 22 23 24 25 26 27    18 19 20 21 22 23 24    22 23 24 25 26 27 28    \n" +
 				"28 29 30 31             25 26 27 28 29 30 31    29 30                   \n\n" +
-				"    October 2024           November 2024           December 2024        \n" +
+				"      October                 November                December          \n" +
 				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
 				"       1  2  3  4  5                    1  2     1  2  3  4  5  6  7    \n" +
 				" 6  7  8  9 10 11 12     3  4  5  6  7  8  9     8  9 10 11 12 13 14    \n" +
@@ -507,3 +625,154 @@ func TestDumpYear(t *testing.T) {
 		})
 	}
 }
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRenderMonth(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderMonth(&buf, time.February, 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, "   February 2024    \n"+
+		"Su Mo Tu We Th Fr Sa\n"+
+		"             1  2  3 \n"+
+		" 4  5  6  7  8  9 10 \n"+
+		"11 12 13 14 15 16 17 \n"+
+		"18 19 20 21 22 23 24 \n"+
+		"25 26 27 28 29 \n", stripAnsiCodes(buf.String()))
+
+	err = RenderMonth(failingWriter{}, time.February, 2024)
+	assert.ErrorContains(t, err, "disk full", "RenderMonth should return write errors")
+}
+
+func TestRenderYear(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderYear(&buf, 2023, WithFirstWeekday(time.Monday))
+	assert.NoError(t, err)
+	lines := strings.Split(stripAnsiCodes(buf.String()), "\n")
+	assert.Equal(t, "                                2023", lines[0])
+	assert.Equal(t, "", lines[1])
+	assert.Equal(t, "      January                 February                 March            ", lines[2])
+	assert.Equal(t, "Mo Tu We Th Fr Sa Su    Mo Tu We Th Fr Sa Su    Mo Tu We Th Fr Sa Su    ", lines[3])
+	assert.Equal(t, "                   1           1  2  3  4  5           1  2  3  4  5    ", lines[4])
+
+	err = RenderYear(failingWriter{}, 2023)
+	assert.ErrorContains(t, err, "disk full", "RenderYear should return write errors")
+}
+
+func TestRenderMonths(t *testing.T) {
+	tests := []struct {
+		name     string
+		month    time.Month
+		year     int
+		count    int
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:  "across the end of the year",
+			month: time.December,
+			year:  2024,
+			count: 3,
+			expected: "   December 2024            January 2025           February 2025        \n" +
+				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
+				" 1  2  3  4  5  6  7              1  2  3  4                       1    \n" +
+				" 8  9 10 11 12 13 14     5  6  7  8  9 10 11     2  3  4  5  6  7  8    \n" +
+				"15 16 17 18 19 20 21    12 13 14 15 16 17 18     9 10 11 12 13 14 15    \n" +
+				"22 23 24 25 26 27 28    19 20 21 22 23 24 25    16 17 18 19 20 21 22    \n" +
+				"29 30 31                26 27 28 29 30 31       23 24 25 26 27 28       \n\n",
+		},
+		{
+			name:  "incomplete last row",
+			month: time.November,
+			year:  2024,
+			count: 4,
+			expected: "   November 2024           December 2024            January 2025        \n" +
+				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa    \n" +
+				"                1  2     1  2  3  4  5  6  7              1  2  3  4    \n" +
+				" 3  4  5  6  7  8  9     8  9 10 11 12 13 14     5  6  7  8  9 10 11    \n" +
+				"10 11 12 13 14 15 16    15 16 17 18 19 20 21    12 13 14 15 16 17 18    \n" +
+				"17 18 19 20 21 22 23    22 23 24 25 26 27 28    19 20 21 22 23 24 25    \n" +
+				"24 25 26 27 28 29 30    29 30 31                26 27 28 29 30 31       \n\n" +
+				"   February 2025        \n" +
+				"Su Mo Tu We Th Fr Sa    \n" +
+				"                   1    \n" +
+				" 2  3  4  5  6  7  8    \n" +
+				" 9 10 11 12 13 14 15    \n" +
+				"16 17 18 19 20 21 22    \n" +
+				"23 24 25 26 27 28       \n\n",
+		},
+		{
+			name:    "no months",
+			month:   time.January,
+			year:    2024,
+			count:   0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderMonths(&buf, tt.month, tt.year, tt.count)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, stripAnsiCodes(buf.String()))
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    yearMonth
+		n        int
+		expected yearMonth
+	}{
+		{name: "same year", start: yearMonth{2024, time.March}, n: 2, expected: yearMonth{2024, time.May}},
+		{name: "into next year", start: yearMonth{2024, time.November}, n: 3, expected: yearMonth{2025, time.February}},
+		{name: "into previous year", start: yearMonth{2024, time.January}, n: -1, expected: yearMonth{2023, time.December}},
+		{name: "several years back", start: yearMonth{2024, time.June}, n: -30, expected: yearMonth{2021, time.December}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.start.addMonths(tt.n))
+		})
+	}
+}
+
+func TestRenderYearMonthsPerRow(t *testing.T) {
+	tests := []struct {
+		name      string
+		perRow    int
+		rows      int
+		lineWidth int
+	}{
+		{name: "six months per row", perRow: 6, rows: 2, lineWidth: 6 * 24},
+		{name: "one month per row", perRow: 1, rows: 12, lineWidth: 24},
+		{name: "twelve months per row", perRow: 12, rows: 1, lineWidth: 12 * 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderYear(&buf, 2024, WithMonthsPerRow(tt.perRow))
+			assert.NoError(t, err)
+
+			blocks := strings.Split(strings.TrimSuffix(stripAnsiCodes(buf.String()), "\n\n"), "\n\n")
+			assert.Len(t, blocks, tt.rows+1, "the year banner is a block of its own")
+			assert.Equal(t, "2024", strings.TrimSpace(blocks[0]))
+			for _, line := range strings.Split(blocks[1], "\n") {
+				assert.Equal(t, tt.lineWidth, DisplayWidth(line))
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+)
+
+// ColorMode selects whether calendars are highlighted with ANSI escape
+// sequences.
+type ColorMode int
+
+const (
+	// ColorAuto highlights output written to a terminal, unless the NO_COLOR
+	// environment variable is set or TERM is "dumb".
+	ColorAuto ColorMode = iota
+	// ColorAlways highlights output wherever it is written.
+	ColorAlways
+	// ColorNever writes plain text.
+	ColorNever
+)
+
+// String returns the name accepted by ParseColorMode.
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAuto:
+		return "auto"
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseColorMode parses "auto", "always" or "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	for m := ColorAuto; m <= ColorNever; m++ {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return ColorAuto, errors.Errorf("invalid color mode %q, expected auto, always or never", s)
+}
+
+// Enabled reports whether output written to w should be highlighted. In
+// ColorAuto mode that is the case when w is a terminal, NO_COLOR is unset or
+// empty and TERM is not "dumb"; see https://no-color.org.
+func (m ColorMode) Enabled(w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
+
+// paint formats s in style if highlighting is enabled. The decision is made
+// by the options rather than by the global color.NoColor.
+func (o *options) paint(style Style, s string) string {
+	if !o.colorize || style.IsZero() {
+		return s
+	}
+	c := color.New(style.attrs...)
+	c.EnableColor()
+	return c.Sprint(s)
+}
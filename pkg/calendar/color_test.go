@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColorMode(t *testing.T) {
+	for _, m := range []ColorMode{ColorAuto, ColorAlways, ColorNever} {
+		parsed, err := ParseColorMode(m.String())
+		assert.NoError(t, err)
+		assert.Equal(t, m, parsed, "ParseColorMode should round-trip String")
+	}
+
+	_, err := ParseColorMode("sometimes")
+	assert.Error(t, err)
+}
+
+func TestColorModeEnabled(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	assert.NoError(t, err)
+	defer devNull.Close()
+
+	tests := []struct {
+		name     string
+		mode     ColorMode
+		noColor  string
+		w        io.Writer
+		expected bool
+	}{
+		{name: "always", mode: ColorAlways, w: &bytes.Buffer{}, expected: true},
+		{name: "always overrides NO_COLOR", mode: ColorAlways, noColor: "1", w: &bytes.Buffer{}, expected: true},
+		{name: "never", mode: ColorNever, w: &bytes.Buffer{}, expected: false},
+		{name: "auto on a buffer", mode: ColorAuto, w: &bytes.Buffer{}, expected: false},
+		{name: "auto on a file that is not a terminal", mode: ColorAuto, w: devNull, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			assert.Equal(t, tt.expected, tt.mode.Enabled(tt.w))
+		})
+	}
+}
+
+func TestRenderMonthColor(t *testing.T) {
+	today := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		mode      ColorMode
+		highlight bool
+	}{
+		{name: "auto writing to a buffer", mode: ColorAuto, highlight: false},
+		{name: "always", mode: ColorAlways, highlight: true},
+		{name: "never", mode: ColorNever, highlight: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, layout := range []Layout{LayoutHorizontal, LayoutVertical} {
+				var buf bytes.Buffer
+				err := RenderMonth(&buf, time.October, 2026, WithToday(today), WithColor(tt.mode), WithLayout(layout))
+				assert.NoError(t, err)
+				assert.Equal(t, tt.highlight, bytes.Contains(buf.Bytes(), []byte("\x1b[")), layout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"time"
+)
+
+// Easter returns the date of Easter Sunday in the given year as reckoned by
+// the Western churches, at midnight UTC in the proleptic Gregorian calendar.
+// It uses the anonymous Gregorian algorithm (Meeus/Jones/Butcher) for all
+// years, including those before the calendar reform; Reform.Easter uses the
+// Julian computus before the reform.
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// OrthodoxEaster returns the date of Easter Sunday in the given year as
+// reckoned by the Orthodox churches, which apply the computus to the Julian
+// calendar. Like Easter, it returns the date at midnight UTC in the proleptic
+// Gregorian calendar, e.g. April 12, 2026 rather than the Julian March 30.
+func OrthodoxEaster(year int) time.Time {
+	d := julianEaster(year)
+	return jdnToTime(julianToJDN(d.year, d.month, d.day))
+}
+
+// Easter returns the date of Western Easter Sunday in the given year under
+// the reform, like "ncal -e": the computus is applied to the Julian calendar
+// while it is in use and to the Gregorian calendar after the reform. Like the
+// function Easter, it returns the date at midnight UTC in the proleptic
+// Gregorian calendar; Date gives the date as written under the reform.
+func (r Reform) Easter(year int) time.Time {
+	if r.isJulian(julianEaster(year)) {
+		return OrthodoxEaster(year)
+	}
+	return Easter(year)
+}
+
+// julianEaster returns the date of Easter Sunday in the Julian calendar, by
+// the Julian computus (Meeus).
+func julianEaster(year int) civilDate {
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+	return civilDate{year, time.Month(month), day}
+}
@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+	}{
+		{1818, time.March, 22},
+		{1943, time.April, 25},
+		{2000, time.April, 23},
+		{2024, time.March, 31},
+		{2025, time.April, 20},
+		{2026, time.April, 5},
+		{2038, time.April, 25},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, time.Date(tt.year, tt.month, tt.day, 0, 0, 0, 0, time.UTC), Easter(tt.year), "Easter %d", tt.year)
+	}
+}
+
+func TestOrthodoxEaster(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+	}{
+		{1918, time.May, 5},
+		{2008, time.April, 27},
+		{2010, time.April, 4},
+		{2024, time.May, 5},
+		{2025, time.April, 20},
+		{2026, time.April, 12},
+		{2027, time.May, 2},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, time.Date(tt.year, tt.month, tt.day, 0, 0, 0, 0, time.UTC), OrthodoxEaster(tt.year), "Orthodox Easter %d", tt.year)
+	}
+}
+
+func TestEasterIsSunday(t *testing.T) {
+	for year := 1; year <= 9999; year++ {
+		if Easter(year).Weekday() != time.Sunday || OrthodoxEaster(year).Weekday() != time.Sunday {
+			t.Fatalf("Easter %d is not on a Sunday", year)
+		}
+	}
+}
+
+func TestReformEaster(t *testing.T) {
+	tests := []struct {
+		name   string
+		reform Reform
+		year   int
+		// month and day are the date as written under the reform.
+		month time.Month
+		day   int
+	}{
+		{name: "Julian before the British reform", reform: Reform1752, year: 1500, month: time.April, day: 19},
+		{name: "Julian in the year of the reform", reform: Reform1752, year: 1752, month: time.March, day: 29},
+		{name: "Gregorian after the British reform", reform: Reform1752, year: 2026, month: time.April, day: 5},
+		{name: "Gregorian after the Catholic reform", reform: Reform1582, year: 1583, month: time.April, day: 10},
+		{name: "Julian in the year of the Catholic reform", reform: Reform1582, year: 1582, month: time.April, day: 15},
+		{name: "proleptic Gregorian", reform: ReformGregorian, year: 1500, month: time.April, day: 1},
+		{name: "always Julian", reform: ReformJulian, year: 2026, month: time.March, day: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			easter := tt.reform.Easter(tt.year)
+			assert.Equal(t, time.Sunday, easter.Weekday())
+			year, month, day := tt.reform.Date(easter)
+			assert.Equal(t, tt.year, year)
+			assert.Equal(t, tt.month, month)
+			assert.Equal(t, tt.day, day)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event is a named span of days, such as a meeting or a trip, that is marked
+// in calendars by WithEvents.
+type Event struct {
+	// Name describes the event, e.g. "Sprint review".
+	Name string
+	// Start is the first day of the event at midnight UTC.
+	Start time.Time
+	// End is the day after the last day of the event at midnight UTC. An End
+	// that is not after Start means a single day.
+	End time.Time
+}
+
+// days returns the days of e at midnight UTC.
+func (e *Event) days() []time.Time {
+	year, month, day := e.Start.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	days := []time.Time{start}
+	for d := start.AddDate(0, 0, 1); d.Before(e.End); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+// defaultEventMarker is the character written after days with events.
+const defaultEventMarker = '*'
+
+// eventSet holds events by the days they cover.
+type eventSet struct {
+	events []*Event
+	days   map[time.Time][]*Event
+}
+
+// newEventSet indexes events by day.
+func newEventSet(events []Event) *eventSet {
+	s := &eventSet{days: make(map[time.Time][]*Event)}
+	for i := range events {
+		e := &events[i]
+		s.events = append(s.events, e)
+		for _, d := range e.days() {
+			s.days[d] = append(s.days[d], e)
+		}
+	}
+	return s
+}
+
+// on returns the events on t, a day at midnight UTC.
+func (s *eventSet) on(t time.Time) []*Event {
+	if s == nil {
+		return nil
+	}
+	return s.days[t]
+}
+
+// WithEvents marks the days of the given events with a marker character, see
+// WithEventMarker, and highlights them in the Event style of the theme.
+func WithEvents(events ...Event) Option {
+	return func(o *options) {
+		o.events = newEventSet(events)
+	}
+}
+
+// WithEventMarker sets the character written after days with events, which
+// must take up a single column. The default is '*'; a space leaves the
+// highlighting to the theme.
+func WithEventMarker(marker rune) Option {
+	return func(o *options) {
+		o.eventMarker = marker
+	}
+}
+
+// WithEventLegend lists the events shown in a calendar below it.
+func WithEventLegend(enabled bool) Option {
+	return func(o *options) {
+		o.eventLegend = enabled
+	}
+}
+
+// dayGap returns the space written after day, or the event marker if it has
+// events.
+func (o *options) dayGap(day *Day) string {
+	if day != nil && !day.Outside && len(day.Events) > 0 {
+		return string(o.eventMarker)
+	}
+	return " "
+}
+
+// writeEventLegend writes the events that fall in the given months to b, in
+// order of their start, if WithEventLegend is enabled.
+func writeEventLegend(b *bytes.Buffer, months []*Month, o *options) {
+	if !o.eventLegend || o.events == nil || len(months) == 0 {
+		return
+	}
+	seen := make(map[*Event]bool)
+	var events []*Event
+	for _, m := range months {
+		for _, day := range m.Days() {
+			for _, e := range day.Events {
+				if !seen[e] {
+					seen[e] = true
+					events = append(events, e)
+				}
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	for _, e := range events {
+		when := o.locale.FormatDate(e.Start)
+		if days := e.days(); len(days) > 1 {
+			when += " - " + o.locale.FormatDate(days[len(days)-1])
+		}
+		fmt.Fprintf(b, "%c %s  %s\n", o.eventMarker, when, strings.TrimSpace(e.Name))
+	}
+	if len(events) > 0 {
+		b.WriteRune('\n')
+	}
+}
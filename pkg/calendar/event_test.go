@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvents = []Event{
+	{Name: "Sprint review", Start: date(2026, time.October, 17)},
+	{Name: "Offsite", Start: date(2026, time.October, 26), End: date(2026, time.October, 29)},
+	{Name: "Halloween", Start: date(2026, time.October, 31), End: date(2026, time.November, 1)},
+}
+
+func TestNewMonthEvents(t *testing.T) {
+	m := NewMonth(time.October, 2026, WithEvents(testEvents...))
+	var marked []int
+	for _, day := range m.Days() {
+		if len(day.Events) > 0 {
+			marked = append(marked, day.Day)
+		}
+	}
+	assert.Equal(t, []int{17, 26, 27, 28, 31}, marked)
+	assert.Equal(t, "Offsite", m.Days()[26].Events[0].Name)
+}
+
+func TestRenderMonthEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		render   func(b *bytes.Buffer) error
+		expected []string
+	}{
+		{
+			name: "month with legend",
+			render: func(b *bytes.Buffer) error {
+				return RenderMonth(b, time.October, 2026, WithColor(ColorNever), WithEvents(testEvents...), WithEventLegend(true))
+			},
+			expected: []string{
+				"    October 2026",
+				"Su Mo Tu We Th Fr Sa",
+				"             1  2  3",
+				" 4  5  6  7  8  9 10",
+				"11 12 13 14 15 16 17*",
+				"18 19 20 21 22 23 24",
+				"25 26*27*28*29 30 31*",
+				"",
+				"* October 17, 2026  Sprint review",
+				"* October 26, 2026 - October 28, 2026  Offsite",
+				"* October 31, 2026  Halloween",
+			},
+		},
+		{
+			name: "month of six weeks with legend",
+			render: func(b *bytes.Buffer) error {
+				return RenderMonth(b, time.August, 2026, WithColor(ColorNever), WithEvents(Event{Name: "Offsite", Start: date(2026, time.August, 3)}), WithEventLegend(true))
+			},
+			expected: []string{
+				"    August 2026",
+				"Su Mo Tu We Th Fr Sa",
+				"                   1",
+				" 2  3* 4  5  6  7  8",
+				" 9 10 11 12 13 14 15",
+				"16 17 18 19 20 21 22",
+				"23 24 25 26 27 28 29",
+				"30 31",
+				"",
+				"* August 3, 2026  Offsite",
+			},
+		},
+		{
+			name: "vertical month with legend",
+			render: func(b *bytes.Buffer) error {
+				return RenderMonth(b, time.October, 2026, WithColor(ColorNever), WithEvents(testEvents[0]), WithEventLegend(true), WithLayout(LayoutVertical))
+			},
+			expected: []string{
+				"   October 2026",
+				"Su     4 11 18 25",
+				"Mo     5 12 19 26",
+				"Tu     6 13 20 27",
+				"We     7 14 21 28",
+				"Th  1  8 15 22 29",
+				"Fr  2  9 16 23 30",
+				"Sa  3 10 17*24 31",
+				"",
+				"* October 17, 2026  Sprint review",
+			},
+		},
+		{
+			name: "months side by side",
+			render: func(b *bytes.Buffer) error {
+				return RenderMonths(b, time.October, 2026, 2, WithColor(ColorNever), WithEvents(testEvents...))
+			},
+			expected: []string{
+				"    October 2026           November 2026",
+				"Su Mo Tu We Th Fr Sa    Su Mo Tu We Th Fr Sa",
+				"             1  2  3     1  2  3  4  5  6  7",
+				" 4  5  6  7  8  9 10     8  9 10 11 12 13 14",
+				"11 12 13 14 15 16 17*   15 16 17 18 19 20 21",
+				"18 19 20 21 22 23 24    22 23 24 25 26 27 28",
+				"25 26*27*28*29 30 31*   29 30",
+			},
+		},
+		{
+			name: "vertical with another marker",
+			render: func(b *bytes.Buffer) error {
+				return RenderMonths(b, time.October, 2026, 2, WithColor(ColorNever), WithEvents(testEvents...), WithLayout(LayoutVertical), WithEventMarker('+'))
+			},
+			expected: []string{
+				"     October 2026      November 2026",
+				"Su     4 11 18 25      1  8 15 22 29",
+				"Mo     5 12 19 26+     2  9 16 23 30",
+				"Tu     6 13 20 27+     3 10 17 24",
+				"We     7 14 21 28+     4 11 18 25",
+				"Th  1  8 15 22 29      5 12 19 26",
+				"Fr  2  9 16 23 30      6 13 20 27",
+				"Sa  3 10 17+24 31+     7 14 21 28",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, tt.render(&b))
+			var lines []string
+			for _, line := range strings.Split(strings.TrimRight(b.String(), "\n"), "\n") {
+				lines = append(lines, strings.TrimRight(line, " "))
+			}
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+}
+
+func TestRenderMonthEventStyle(t *testing.T) {
+	theme := &Theme{Event: MustParseStyle("underline")}
+	lines := DumpMonthToSlice(time.October, 2026, WithColor(ColorAlways), WithTheme(theme), WithEvents(testEvents...))
+	assert.Contains(t, lines[6], "\x1b[4m26\x1b[24m*")
+}
@@ -0,0 +1,539 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dateRuleKind is the type of a DateRule.
+type dateRuleKind int
+
+const (
+	// fixedDate is the same month and day every year.
+	fixedDate dateRuleKind = iota
+	// nthWeekday is the nth weekday of a month, counting from its end if n
+	// is negative.
+	nthWeekday
+	// weekdayBefore is the last weekday strictly before a month and day.
+	weekdayBefore
+	// weekdayAfter is the first weekday strictly after a month and day.
+	weekdayAfter
+	// easterOffset is a number of days after Western Easter Sunday.
+	easterOffset
+	// orthodoxEasterOffset is a number of days after Orthodox Easter
+	// Sunday.
+	orthodoxEasterOffset
+)
+
+// easterNames maps the names of the Easter rules to their kinds.
+var easterNames = map[dateRuleKind]string{
+	easterOffset:         "easter",
+	orthodoxEasterOffset: "orthodox-easter",
+}
+
+// DateRule computes the date of a recurring holiday in a given year. Rules
+// are written as text, see ParseDateRule.
+type DateRule struct {
+	kind    dateRuleKind
+	month   time.Month
+	day     int
+	weekday time.Weekday
+	n       int
+	offset  int
+	// year restricts a fixedDate rule to a single year when it is not zero.
+	year int
+}
+
+// ordinals maps the words accepted for the nth weekday of a month to n.
+var ordinals = map[string]int{
+	"1st": 1, "first": 1,
+	"2nd": 2, "second": 2,
+	"3rd": 3, "third": 3,
+	"4th": 4, "fourth": 4,
+	"5th": 5, "fifth": 5,
+	"last": -1,
+}
+
+// ParseDateRule parses the date of a holiday. The forms are, case
+// insensitively:
+//
+//	07-04              a fixed month and day, also written "jul 4"
+//	2026-08-14         a single date in a given year
+//	4th thu of nov     the nth weekday of a month, 1st to 5th or last
+//	mon before may 25  the last weekday before a date
+//	tue after nov 1    the first weekday after a date
+//	easter-2           a number of days before or after Easter Sunday
+//	orthodox-easter+1  the same for Orthodox Easter Sunday
+func ParseDateRule(s string) (DateRule, error) {
+	words := strings.Fields(strings.ToLower(s))
+	invalid := errors.Errorf("invalid date rule %q", s)
+
+	if len(words) == 1 {
+		for kind, name := range easterNames {
+			rest, ok := strings.CutPrefix(words[0], name)
+			if !ok {
+				continue
+			}
+			r := DateRule{kind: kind}
+			if rest != "" {
+				n, err := strconv.Atoi(rest)
+				if err != nil || (rest[0] != '+' && rest[0] != '-') {
+					return DateRule{}, invalid
+				}
+				r.offset = n
+			}
+			return r, nil
+		}
+	}
+
+	switch {
+	case len(words) == 1 && strings.Count(words[0], "-") == 2:
+		t, err := time.Parse("2006-01-02", words[0])
+		if err != nil {
+			return DateRule{}, invalid
+		}
+		return DateRule{kind: fixedDate, month: t.Month(), day: t.Day(), year: t.Year()}, nil
+
+	case len(words) == 1:
+		month, day, ok := strings.Cut(words[0], "-")
+		if !ok {
+			return DateRule{}, invalid
+		}
+		m, err := strconv.Atoi(month)
+		if err != nil || m < 1 || m > 12 {
+			return DateRule{}, invalid
+		}
+		return newFixedRule(time.Month(m), day, invalid)
+
+	case len(words) == 2:
+		m, err := parseMonthName(words[0])
+		if err != nil {
+			return DateRule{}, invalid
+		}
+		return newFixedRule(m, words[1], invalid)
+
+	case len(words) == 4 && words[2] == "of":
+		n, ok := ordinals[words[0]]
+		if !ok {
+			return DateRule{}, invalid
+		}
+		weekday, err := ParseWeekday(words[1])
+		if err != nil {
+			return DateRule{}, invalid
+		}
+		m, err := parseMonthName(words[3])
+		if err != nil {
+			return DateRule{}, invalid
+		}
+		return DateRule{kind: nthWeekday, month: m, weekday: weekday, n: n}, nil
+
+	case len(words) == 4 && (words[1] == "before" || words[1] == "after"):
+		weekday, err := ParseWeekday(words[0])
+		if err != nil {
+			return DateRule{}, invalid
+		}
+		m, err := parseMonthName(words[2])
+		if err != nil {
+			return DateRule{}, invalid
+		}
+		r, err := newFixedRule(m, words[3], invalid)
+		if err != nil {
+			return DateRule{}, err
+		}
+		r.kind, r.weekday = weekdayBefore, weekday
+		if words[1] == "after" {
+			r.kind = weekdayAfter
+		}
+		return r, nil
+	}
+	return DateRule{}, invalid
+}
+
+// newFixedRule returns the rule for a month and a day given as text.
+func newFixedRule(month time.Month, day string, invalid error) (DateRule, error) {
+	d, err := strconv.Atoi(day)
+	if err != nil || d < 1 || d > gregorianDaysIn(month, 2024) {
+		return DateRule{}, invalid
+	}
+	return DateRule{kind: fixedDate, month: month, day: d}, nil
+}
+
+// MustParseDateRule is like ParseDateRule but panics if s is invalid. It is
+// meant for initializing built-in holidays.
+func MustParseDateRule(s string) DateRule {
+	r, err := ParseDateRule(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// parseMonthName parses an English month name, which may be abbreviated to
+// three or more letters.
+func parseMonthName(s string) (time.Month, error) {
+	if len(s) >= 3 {
+		for m := time.January; m <= time.December; m++ {
+			if strings.HasPrefix(strings.ToLower(m.String()), strings.ToLower(s)) {
+				return m, nil
+			}
+		}
+	}
+	return 0, errors.Errorf("invalid month %q", s)
+}
+
+// shortName returns the lower-case three-letter abbreviation of an English
+// month or weekday name.
+func shortName(name string) string {
+	return strings.ToLower(name[:3])
+}
+
+// String returns the rule as accepted by ParseDateRule.
+func (r DateRule) String() string {
+	switch r.kind {
+	case nthWeekday:
+		nth := "last"
+		for word, n := range ordinals {
+			if n == r.n && len(word) == 3 {
+				nth = word
+			}
+		}
+		return fmt.Sprintf("%s %s of %s", nth, shortName(r.weekday.String()), shortName(r.month.String()))
+	case weekdayBefore:
+		return fmt.Sprintf("%s before %s %d", shortName(r.weekday.String()), shortName(r.month.String()), r.day)
+	case weekdayAfter:
+		return fmt.Sprintf("%s after %s %d", shortName(r.weekday.String()), shortName(r.month.String()), r.day)
+	case easterOffset, orthodoxEasterOffset:
+		if r.offset == 0 {
+			return easterNames[r.kind]
+		}
+		return fmt.Sprintf("%s%+d", easterNames[r.kind], r.offset)
+	default:
+		if r.year != 0 {
+			return fmt.Sprintf("%04d-%02d-%02d", r.year, r.month, r.day)
+		}
+		return fmt.Sprintf("%02d-%02d", r.month, r.day)
+	}
+}
+
+// Date returns the date of the rule in the given year, at midnight UTC in the
+// proleptic Gregorian calendar. It reports false if the rule has no date
+// that year, such as February 29 in a common year or the 5th Monday of a
+// month with four.
+func (r DateRule) Date(year int) (time.Time, bool) {
+	switch r.kind {
+	case nthWeekday:
+		if r.n < 0 {
+			last := time.Date(year, r.month+1, 0, 0, 0, 0, 0, time.UTC)
+			return last.AddDate(0, 0, -((int(last.Weekday())-int(r.weekday)+7)%7 + 7*(-r.n-1))), true
+		}
+		first := time.Date(year, r.month, 1, 0, 0, 0, 0, time.UTC)
+		t := first.AddDate(0, 0, (int(r.weekday)-int(first.Weekday())+7)%7+7*(r.n-1))
+		return t, t.Month() == r.month
+	case weekdayBefore:
+		date := time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+		return date.AddDate(0, 0, -((int(date.Weekday())-int(r.weekday)+6)%7 + 1)), true
+	case weekdayAfter:
+		date := time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+		return date.AddDate(0, 0, (int(r.weekday)-int(date.Weekday())+6)%7+1), true
+	case easterOffset:
+		return Easter(year).AddDate(0, 0, r.offset), true
+	case orthodoxEasterOffset:
+		return OrthodoxEaster(year).AddDate(0, 0, r.offset), true
+	default:
+		t := time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+		return t, t.Month() == r.month && (r.year == 0 || r.year == year)
+	}
+}
+
+// Observance says on which day a holiday falling on a Saturday or Sunday is
+// observed.
+type Observance int
+
+const (
+	// ObserveNone leaves holidays on the weekend where they fall.
+	ObserveNone Observance = iota
+	// ObserveNearestWeekday moves Saturday holidays to the Friday before
+	// and Sunday holidays to the Monday after, as in the United States.
+	ObserveNearestWeekday
+	// ObserveNextWeekday moves weekend holidays to the next weekday that is
+	// not already a holiday, as the substitute days of the United Kingdom
+	// and Canada.
+	ObserveNextWeekday
+)
+
+// String returns the name accepted by ParseObservance.
+func (o Observance) String() string {
+	switch o {
+	case ObserveNone:
+		return "none"
+	case ObserveNearestWeekday:
+		return "nearest-weekday"
+	case ObserveNextWeekday:
+		return "next-weekday"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseObservance parses "none", "nearest-weekday" or "next-weekday".
+func ParseObservance(s string) (Observance, error) {
+	for o := ObserveNone; o <= ObserveNextWeekday; o++ {
+		if strings.EqualFold(s, o.String()) {
+			return o, nil
+		}
+	}
+	return ObserveNone, errors.Errorf("invalid observance %q, expected none, nearest-weekday or next-weekday", s)
+}
+
+// HolidayRule describes a recurring holiday.
+type HolidayRule struct {
+	// Name is the name of the holiday.
+	Name string
+	// Date computes the date of the holiday.
+	Date DateRule
+	// Observed selects the day off when the holiday falls on a weekend.
+	Observed Observance
+	// Since and Until limit the rule to a range of years. Zero means no
+	// limit.
+	Since, Until int
+	// Days is the length of the holiday in days, such as 5 for a shutdown
+	// week. Zero means a single day.
+	Days int
+}
+
+// appliesIn reports whether r is in effect in the given year.
+func (r *HolidayRule) appliesIn(year int) bool {
+	return (r.Since == 0 || year >= r.Since) && (r.Until == 0 || year <= r.Until)
+}
+
+// dates returns the days of r that fall in the given year, including the end
+// of a holiday that starts in the year before.
+func (r *HolidayRule) dates(year int) []time.Time {
+	days := max(r.Days, 1)
+	var dates []time.Time
+	for y := year - 1; y <= year; y++ {
+		if (days == 1 && y < year) || !r.appliesIn(y) {
+			continue
+		}
+		start, ok := r.Date.Date(y)
+		if !ok {
+			continue
+		}
+		for i := range days {
+			if date := start.AddDate(0, 0, i); date.Year() == year {
+				dates = append(dates, date)
+			}
+		}
+	}
+	return dates
+}
+
+// Holiday is a holiday in a particular year.
+type Holiday struct {
+	// Name is the name of the holiday.
+	Name string
+	// Date is the holiday at midnight UTC.
+	Date time.Time
+	// Observed is the day off, which differs from Date if the holiday falls
+	// on a weekend and is observed on a weekday.
+	Observed time.Time
+}
+
+// Region is a set of holidays, such as the federal holidays of the United
+// States.
+type Region struct {
+	// Code is the name accepted by LookupRegion, e.g. "US".
+	Code string
+	// Name describes the region, e.g. "United States (federal)".
+	Name string
+	// Rules lists the holidays of the region.
+	Rules []HolidayRule
+}
+
+// Holidays returns the holidays of r that fall in the given year, ordered by
+// date. Observed days may fall in an adjacent year, as when New Year's Day on
+// a Saturday is observed on the Friday before.
+func (r *Region) Holidays(year int) []Holiday {
+	var holidays []Holiday
+	var observances []Observance
+	taken := make(map[time.Time]bool)
+	for _, rule := range r.Rules {
+		for _, date := range rule.dates(year) {
+			holidays = append(holidays, Holiday{Name: rule.Name, Date: date, Observed: date})
+			observances = append(observances, rule.Observed)
+			taken[date] = true
+		}
+	}
+
+	// Substitute days are handed out in date order, so that Boxing Day on
+	// a Sunday after Christmas on a Saturday moves to the Tuesday.
+	order := make([]int, len(holidays))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return holidays[order[i]].Date.Before(holidays[order[j]].Date) })
+	for _, i := range order {
+		h := &holidays[i]
+		if !isSaturdayOrSunday(h.Date) {
+			continue
+		}
+		switch observances[i] {
+		case ObserveNearestWeekday:
+			if h.Date.Weekday() == time.Saturday {
+				h.Observed = h.Date.AddDate(0, 0, -1)
+			} else {
+				h.Observed = h.Date.AddDate(0, 0, 1)
+			}
+		case ObserveNextWeekday:
+			d := h.Date.AddDate(0, 0, 1)
+			for isSaturdayOrSunday(d) || taken[d] {
+				d = d.AddDate(0, 0, 1)
+			}
+			h.Observed = d
+			taken[d] = true
+		}
+	}
+
+	sorted := make([]Holiday, len(order))
+	for k, i := range order {
+		sorted[k] = holidays[i]
+	}
+	return sorted
+}
+
+// isSaturdayOrSunday reports whether t falls on a Saturday or a Sunday. The
+// observance of holidays does not depend on WithWeekend.
+func isSaturdayOrSunday(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// holidaySet holds the dates of the holidays of some regions, including the
+// observed days.
+type holidaySet struct {
+	regions []*Region
+	years   map[int]map[time.Time]bool
+}
+
+// contains reports whether t is a holiday or an observed day off in one of
+// the regions.
+func (s *holidaySet) contains(t time.Time) bool {
+	if s == nil || len(s.regions) == 0 {
+		return false
+	}
+	year := t.Year()
+	if _, ok := s.years[year]; !ok {
+		dates := make(map[time.Time]bool)
+		// Observed days may spill over from the adjacent years.
+		for y := year - 1; y <= year+1; y++ {
+			for _, r := range s.regions {
+				for _, h := range r.Holidays(y) {
+					dates[h.Date] = true
+					dates[h.Observed] = true
+				}
+			}
+		}
+		s.years[year] = dates
+	}
+	return s.years[year][t]
+}
+
+// regions holds the built-in holiday regions by code. Only regular rules are
+// included; one-off holidays such as royal jubilees are not.
+var regions = map[string]*Region{
+	"US": {
+		Code: "US",
+		Name: "United States (federal)",
+		Rules: []HolidayRule{
+			{Name: "New Year's Day", Date: MustParseDateRule("01-01"), Observed: ObserveNearestWeekday},
+			{Name: "Birthday of Martin Luther King, Jr.", Date: MustParseDateRule("3rd mon of jan"), Since: 1986},
+			{Name: "Washington's Birthday", Date: MustParseDateRule("3rd mon of feb")},
+			{Name: "Memorial Day", Date: MustParseDateRule("last mon of may")},
+			{Name: "Juneteenth National Independence Day", Date: MustParseDateRule("06-19"), Observed: ObserveNearestWeekday, Since: 2021},
+			{Name: "Independence Day", Date: MustParseDateRule("07-04"), Observed: ObserveNearestWeekday},
+			{Name: "Labor Day", Date: MustParseDateRule("1st mon of sep")},
+			{Name: "Columbus Day", Date: MustParseDateRule("2nd mon of oct")},
+			{Name: "Veterans Day", Date: MustParseDateRule("11-11"), Observed: ObserveNearestWeekday},
+			{Name: "Thanksgiving Day", Date: MustParseDateRule("4th thu of nov")},
+			{Name: "Christmas Day", Date: MustParseDateRule("12-25"), Observed: ObserveNearestWeekday},
+		},
+	},
+	"GB": {
+		Code: "GB",
+		Name: "United Kingdom (England and Wales)",
+		Rules: []HolidayRule{
+			{Name: "New Year's Day", Date: MustParseDateRule("01-01"), Observed: ObserveNextWeekday},
+			{Name: "Good Friday", Date: MustParseDateRule("easter-2")},
+			{Name: "Easter Monday", Date: MustParseDateRule("easter+1")},
+			{Name: "Early May bank holiday", Date: MustParseDateRule("1st mon of may"), Since: 1978},
+			{Name: "Spring bank holiday", Date: MustParseDateRule("last mon of may"), Since: 1971},
+			{Name: "Summer bank holiday", Date: MustParseDateRule("last mon of aug"), Since: 1971},
+			{Name: "Christmas Day", Date: MustParseDateRule("12-25"), Observed: ObserveNextWeekday},
+			{Name: "Boxing Day", Date: MustParseDateRule("12-26"), Observed: ObserveNextWeekday},
+		},
+	},
+	"DE": {
+		Code: "DE",
+		Name: "Germany (nationwide)",
+		Rules: []HolidayRule{
+			{Name: "Neujahr", Date: MustParseDateRule("01-01")},
+			{Name: "Karfreitag", Date: MustParseDateRule("easter-2")},
+			{Name: "Ostermontag", Date: MustParseDateRule("easter+1")},
+			{Name: "Tag der Arbeit", Date: MustParseDateRule("05-01")},
+			{Name: "Christi Himmelfahrt", Date: MustParseDateRule("easter+39")},
+			{Name: "Pfingstmontag", Date: MustParseDateRule("easter+50")},
+			{Name: "Tag der Deutschen Einheit", Date: MustParseDateRule("10-03"), Since: 1990},
+			{Name: "1. Weihnachtstag", Date: MustParseDateRule("12-25")},
+			{Name: "2. Weihnachtstag", Date: MustParseDateRule("12-26")},
+		},
+	},
+	"CA": {
+		Code: "CA",
+		Name: "Canada (federal)",
+		Rules: []HolidayRule{
+			{Name: "New Year's Day", Date: MustParseDateRule("01-01"), Observed: ObserveNextWeekday},
+			{Name: "Good Friday", Date: MustParseDateRule("easter-2")},
+			{Name: "Victoria Day", Date: MustParseDateRule("mon before may 25")},
+			{Name: "Canada Day", Date: MustParseDateRule("07-01"), Observed: ObserveNextWeekday},
+			{Name: "Labour Day", Date: MustParseDateRule("1st mon of sep")},
+			{Name: "National Day for Truth and Reconciliation", Date: MustParseDateRule("09-30"), Observed: ObserveNextWeekday, Since: 2021},
+			{Name: "Thanksgiving", Date: MustParseDateRule("2nd mon of oct")},
+			{Name: "Remembrance Day", Date: MustParseDateRule("11-11"), Observed: ObserveNextWeekday},
+			{Name: "Christmas Day", Date: MustParseDateRule("12-25"), Observed: ObserveNextWeekday},
+			{Name: "Boxing Day", Date: MustParseDateRule("12-26"), Observed: ObserveNextWeekday},
+		},
+	},
+}
+
+// regionAliases maps alternative names accepted by LookupRegion to codes.
+var regionAliases = map[string]string{
+	"UK": "GB",
+}
+
+// Regions returns the codes of the built-in holiday regions in alphabetical
+// order.
+func Regions() []string {
+	codes := make([]string, 0, len(regions))
+	for code := range regions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// LookupRegion returns the built-in holiday region with the given code, such
+// as "US", "GB" (or "UK"), "DE" or "CA". Codes are case insensitive.
+func LookupRegion(code string) (*Region, error) {
+	upper := strings.ToUpper(code)
+	if alias, ok := regionAliases[upper]; ok {
+		upper = alias
+	}
+	if r, ok := regions[upper]; ok {
+		return r, nil
+	}
+	return nil, errors.Errorf("unknown holiday region %q, expected one of %s", code, strings.Join(Regions(), ", "))
+}
@@ -0,0 +1,219 @@
+package calendar
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseDateRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		year     int
+		expected time.Time
+		str      string
+		wantErr  bool
+	}{
+		{name: "fixed", input: "07-04", year: 2026, expected: date(2026, time.July, 4), str: "07-04"},
+		{name: "fixed by name", input: "July 4", year: 2026, expected: date(2026, time.July, 4), str: "07-04"},
+		{name: "nth weekday", input: "4th Thu of Nov", year: 2026, expected: date(2026, time.November, 26), str: "4th thu of nov"},
+		{name: "nth weekday in words", input: "first monday of september", year: 2026, expected: date(2026, time.September, 7), str: "1st mon of sep"},
+		{name: "last weekday", input: "last mon of may", year: 2026, expected: date(2026, time.May, 25), str: "last mon of may"},
+		{name: "last weekday on the last day", input: "last sun of may", year: 2026, expected: date(2026, time.May, 31), str: "last sun of may"},
+		{name: "weekday before", input: "mon before may 25", year: 2026, expected: date(2026, time.May, 18), str: "mon before may 25"},
+		{name: "weekday before in another week", input: "mon before may 25", year: 2027, expected: date(2027, time.May, 24), str: "mon before may 25"},
+		{name: "weekday after", input: "tue after nov 1", year: 2026, expected: date(2026, time.November, 3), str: "tue after nov 1"},
+		{name: "Easter", input: "easter", year: 2026, expected: date(2026, time.April, 5), str: "easter"},
+		{name: "before Easter", input: "Easter-2", year: 2026, expected: date(2026, time.April, 3), str: "easter-2"},
+		{name: "after Easter", input: "easter+50", year: 2026, expected: date(2026, time.May, 25), str: "easter+50"},
+		{name: "Orthodox Easter", input: "orthodox-easter+1", year: 2026, expected: date(2026, time.April, 13), str: "orthodox-easter+1"},
+		{name: "single date", input: "2026-08-14", year: 2026, expected: date(2026, time.August, 14), str: "2026-08-14"},
+		{name: "invalid day", input: "02-30", wantErr: true},
+		{name: "invalid single date", input: "2026-02-29", wantErr: true},
+		{name: "invalid month", input: "13-01", wantErr: true},
+		{name: "sixth weekday", input: "6th mon of may", wantErr: true},
+		{name: "Easter without sign", input: "easter2", wantErr: true},
+		{name: "gibberish", input: "whenever", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseDateRule(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			d, ok := r.Date(tt.year)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, d)
+			assert.Equal(t, tt.str, r.String())
+		})
+	}
+}
+
+func TestDateRuleWithoutDate(t *testing.T) {
+	_, ok := MustParseDateRule("02-29").Date(2026)
+	assert.False(t, ok, "February 29 does not exist in common years")
+	_, ok = MustParseDateRule("5th mon of feb").Date(2026)
+	assert.False(t, ok, "February 2026 has four Mondays")
+	_, ok = MustParseDateRule("2026-08-14").Date(2027)
+	assert.False(t, ok, "a single date is in one year only")
+	d, ok := MustParseDateRule("5th fri of may").Date(2026)
+	assert.True(t, ok)
+	assert.Equal(t, date(2026, time.May, 29), d)
+}
+
+func TestRegionHolidays(t *testing.T) {
+	type holiday struct {
+		name     string
+		date     time.Time
+		observed time.Time
+	}
+	tests := []struct {
+		name     string
+		region   string
+		year     int
+		expected []holiday
+	}{
+		{
+			name:   "US federal holidays 2026",
+			region: "US",
+			year:   2026,
+			expected: []holiday{
+				{"New Year's Day", date(2026, time.January, 1), date(2026, time.January, 1)},
+				{"Birthday of Martin Luther King, Jr.", date(2026, time.January, 19), date(2026, time.January, 19)},
+				{"Washington's Birthday", date(2026, time.February, 16), date(2026, time.February, 16)},
+				{"Memorial Day", date(2026, time.May, 25), date(2026, time.May, 25)},
+				{"Juneteenth National Independence Day", date(2026, time.June, 19), date(2026, time.June, 19)},
+				{"Independence Day", date(2026, time.July, 4), date(2026, time.July, 3)},
+				{"Labor Day", date(2026, time.September, 7), date(2026, time.September, 7)},
+				{"Columbus Day", date(2026, time.October, 12), date(2026, time.October, 12)},
+				{"Veterans Day", date(2026, time.November, 11), date(2026, time.November, 11)},
+				{"Thanksgiving Day", date(2026, time.November, 26), date(2026, time.November, 26)},
+				{"Christmas Day", date(2026, time.December, 25), date(2026, time.December, 25)},
+			},
+		},
+		{
+			name:   "UK substitute days 2021",
+			region: "uk",
+			year:   2021,
+			expected: []holiday{
+				{"New Year's Day", date(2021, time.January, 1), date(2021, time.January, 1)},
+				{"Good Friday", date(2021, time.April, 2), date(2021, time.April, 2)},
+				{"Easter Monday", date(2021, time.April, 5), date(2021, time.April, 5)},
+				{"Early May bank holiday", date(2021, time.May, 3), date(2021, time.May, 3)},
+				{"Spring bank holiday", date(2021, time.May, 31), date(2021, time.May, 31)},
+				{"Summer bank holiday", date(2021, time.August, 30), date(2021, time.August, 30)},
+				{"Christmas Day", date(2021, time.December, 25), date(2021, time.December, 27)},
+				{"Boxing Day", date(2021, time.December, 26), date(2021, time.December, 28)},
+			},
+		},
+		{
+			name:   "Germany 2026",
+			region: "DE",
+			year:   2026,
+			expected: []holiday{
+				{"Neujahr", date(2026, time.January, 1), date(2026, time.January, 1)},
+				{"Karfreitag", date(2026, time.April, 3), date(2026, time.April, 3)},
+				{"Ostermontag", date(2026, time.April, 6), date(2026, time.April, 6)},
+				{"Tag der Arbeit", date(2026, time.May, 1), date(2026, time.May, 1)},
+				{"Christi Himmelfahrt", date(2026, time.May, 14), date(2026, time.May, 14)},
+				{"Pfingstmontag", date(2026, time.May, 25), date(2026, time.May, 25)},
+				{"Tag der Deutschen Einheit", date(2026, time.October, 3), date(2026, time.October, 3)},
+				{"1. Weihnachtstag", date(2026, time.December, 25), date(2026, time.December, 25)},
+				{"2. Weihnachtstag", date(2026, time.December, 26), date(2026, time.December, 26)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := LookupRegion(tt.region)
+			assert.NoError(t, err)
+			var result []holiday
+			for _, h := range r.Holidays(tt.year) {
+				result = append(result, holiday{h.Name, h.Date, h.Observed})
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRegionHolidaysObserved(t *testing.T) {
+	observed := func(region string, year int, name string) time.Time {
+		r, err := LookupRegion(region)
+		assert.NoError(t, err)
+		for _, h := range r.Holidays(year) {
+			if h.Name == name {
+				return h.Observed
+			}
+		}
+		t.Fatalf("no %s in %s %d", name, region, year)
+		return time.Time{}
+	}
+
+	assert.Equal(t, date(2021, time.December, 31), observed("US", 2022, "New Year's Day"), "observed in the previous year")
+	assert.Equal(t, date(2022, time.December, 27), observed("GB", 2022, "Christmas Day"), "Boxing Day keeps Monday")
+	assert.Equal(t, date(2023, time.July, 3), observed("CA", 2023, "Canada Day"))
+	assert.Equal(t, date(2026, time.May, 18), observed("CA", 2026, "Victoria Day"))
+}
+
+func TestRegionHolidaysSince(t *testing.T) {
+	us, err := LookupRegion("US")
+	assert.NoError(t, err)
+	assert.Len(t, us.Holidays(2020), 10, "Juneteenth became a federal holiday in 2021")
+	assert.Len(t, us.Holidays(1985), 9, "Martin Luther King Jr. Day was first observed in 1986")
+
+	_, err = LookupRegion("XX")
+	assert.ErrorContains(t, err, "CA, DE, GB, US")
+}
+
+func TestWithHolidays(t *testing.T) {
+	us, err := LookupRegion("US")
+	assert.NoError(t, err)
+
+	var holidays []int
+	for _, day := range NewMonth(time.July, 2026, WithHolidays(us)).Days() {
+		if day.Holiday {
+			holidays = append(holidays, day.Day)
+		}
+	}
+	assert.Equal(t, []int{3, 4}, holidays, "Independence Day and the Friday it is observed on")
+
+	m := NewMonth(time.December, 2021, WithHolidays(us))
+	assert.True(t, m.Days()[30].Holiday, "New Year's Day 2022 is observed on December 31, 2021")
+
+	for _, day := range NewMonth(time.July, 2026).Days() {
+		assert.False(t, day.Holiday, "no holidays without WithHolidays")
+	}
+
+	gb, err := LookupRegion("GB")
+	assert.NoError(t, err)
+	for _, day := range NewMonth(time.December, 1700, WithHolidays(gb), WithReform(Reform1752)).Days() {
+		assert.False(t, day.Holiday, "no holidays before the reform, December %d", day.Day)
+	}
+	m = NewMonth(time.December, 1752, WithHolidays(gb), WithReform(Reform1752))
+	assert.True(t, m.Days()[24].Holiday, "Christmas Day after the reform")
+}
+
+func TestRenderMonthHolidays(t *testing.T) {
+	de, err := LookupRegion("DE")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = RenderMonth(&buf, time.May, 2026, WithHolidays(de), WithColor(ColorAlways), WithFirstWeekday(time.Monday))
+	assert.NoError(t, err)
+	out := buf.String()
+	for _, day := range []string{" 1", "14", "25"} {
+		assert.Contains(t, out, "\x1b[35m"+day+"\x1b[0m", "May %s is a holiday", day)
+	}
+	assert.NotContains(t, out, "\x1b[35m 4\x1b[0m")
+}
@@ -0,0 +1,300 @@
+package calendar
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseHolidayFile reads a file of holidays, such as the office closures of a
+// company, and returns them as a region. The file is in TOML if name ends in
+// ".toml" and in YAML or JSON otherwise, for example
+//
+//	name: Example Corp.
+//	base: us
+//	exclude: [Columbus Day]
+//	holidays:
+//	  - name: Founders' Day
+//	    date: jun 12
+//	    observed: nearest-weekday
+//	    since: 2015
+//	  - name: Summer shutdown
+//	    date: 1st mon of aug
+//	    days: 5
+//	  - name: Office move
+//	    date: 2026-03-13
+//
+// Base lists the built-in regions whose holidays are merged into the file, and
+// exclude drops some of them by name. Dates are written as for ParseDateRule
+// and observances as for ParseObservance. The region is named by the code and
+// name keys, and after the file otherwise.
+//
+// A holiday that repeats the name or the date of another one in the same
+// years is a conflict. Conflicts and invalid rules are errors that give the
+// line number.
+func ParseHolidayFile(name string, data []byte) (*Region, error) {
+	var root *yaml.Node
+	if strings.EqualFold(filepath.Ext(name), ".toml") {
+		var err error
+		if root, err = parseTOML(data); err != nil {
+			return nil, errors.Wrap(err, "invalid holiday file")
+		}
+	} else {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, errors.Wrap(err, "invalid holiday file")
+		}
+		if len(doc.Content) > 0 {
+			root = doc.Content[0]
+		}
+	}
+	if root == nil {
+		return nil, errors.New("empty holiday file")
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.Errorf("line %d: a holiday file must be a mapping", root.Line)
+	}
+
+	code := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	r := &Region{Code: code}
+	var bases []*Region
+	var exclude, holidays []*yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		var err error
+		switch key.Value {
+		case "code":
+			r.Code, err = scalarValue(value, "code")
+		case "name":
+			r.Name, err = scalarValue(value, "name")
+		case "base":
+			bases, err = parseBaseRegions(value)
+		case "exclude":
+			exclude, err = listValue(value, "exclude")
+		case "holidays":
+			if value.Kind != yaml.SequenceNode {
+				return nil, errors.Errorf("line %d: holidays must be a list", value.Line)
+			}
+			holidays = value.Content
+		default:
+			return nil, errors.Errorf("line %d: unknown key %q, expected code, name, base, exclude or holidays", key.Line, key.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Name == "" {
+		r.Name = r.Code
+	}
+
+	// The holidays of the base regions, less the excluded ones.
+	excluded := make(map[string]bool)
+	for _, node := range exclude {
+		if !hasHoliday(bases, node.Value) {
+			if len(bases) == 0 {
+				return nil, errors.Errorf("line %d: cannot exclude %q without a base region", node.Line, node.Value)
+			}
+			return nil, errors.Errorf("line %d: no holiday %q in %s", node.Line, node.Value, regionCodes(bases))
+		}
+		excluded[node.Value] = true
+	}
+	var defined []definedHoliday
+	for _, base := range bases {
+		for _, rule := range base.Rules {
+			// Regions such as US and CA share some holidays.
+			if excluded[rule.Name] || isDefined(defined, rule) {
+				continue
+			}
+			r.Rules = append(r.Rules, rule)
+			defined = append(defined, definedHoliday{rule: rule, region: base.Code})
+		}
+	}
+
+	for _, node := range holidays {
+		rule, err := parseHolidayRule(node)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range defined {
+			if !other.overlaps(rule) {
+				continue
+			}
+			var conflict string
+			switch {
+			case other.rule.Name == rule.Name:
+				conflict = "is already defined"
+			case other.rule.Date == rule.Date:
+				conflict = "falls on the same date as " + strconv.Quote(other.rule.Name)
+			default:
+				continue
+			}
+			if other.region != "" {
+				return nil, errors.Errorf("line %d: holiday %q %s in region %s, exclude it to replace it", node.Line, rule.Name, conflict, other.region)
+			}
+			return nil, errors.Errorf("line %d: holiday %q %s on line %d", node.Line, rule.Name, conflict, other.line)
+		}
+		r.Rules = append(r.Rules, rule)
+		defined = append(defined, definedHoliday{rule: rule, line: node.Line})
+	}
+	return r, nil
+}
+
+// definedHoliday is a rule of a holiday file, which either comes from a base
+// region or is defined on a line of the file.
+type definedHoliday struct {
+	rule   HolidayRule
+	region string
+	line   int
+}
+
+// overlaps reports whether h and rule apply in some common year.
+func (h definedHoliday) overlaps(rule HolidayRule) bool {
+	a, b := h.rule, rule
+	return (a.Until == 0 || b.Since == 0 || b.Since <= a.Until) &&
+		(b.Until == 0 || a.Since == 0 || a.Since <= b.Until)
+}
+
+// isDefined reports whether a holiday with the name and date of rule is
+// already defined.
+func isDefined(defined []definedHoliday, rule HolidayRule) bool {
+	for _, h := range defined {
+		if h.rule.Name == rule.Name && h.rule.Date == rule.Date && h.overlaps(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHoliday reports whether one of the regions has a holiday with the given
+// name.
+func hasHoliday(regions []*Region, name string) bool {
+	for _, r := range regions {
+		for _, rule := range r.Rules {
+			if rule.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// regionCodes returns the codes of the regions separated by commas.
+func regionCodes(regions []*Region) string {
+	codes := make([]string, len(regions))
+	for i, r := range regions {
+		codes[i] = r.Code
+	}
+	return strings.Join(codes, ", ")
+}
+
+// parseBaseRegions parses the base key of a holiday file, a list of built-in
+// region codes written as a list or separated by commas.
+func parseBaseRegions(node *yaml.Node) ([]*Region, error) {
+	codes, err := listValue(node, "base")
+	if err != nil {
+		return nil, err
+	}
+	var bases []*Region
+	for _, code := range codes {
+		for _, c := range strings.Split(code.Value, ",") {
+			base, err := LookupRegion(strings.TrimSpace(c))
+			if err != nil {
+				return nil, errors.Errorf("line %d: %s", code.Line, err)
+			}
+			for _, b := range bases {
+				if b == base {
+					return nil, errors.Errorf("line %d: region %s is listed twice", code.Line, base.Code)
+				}
+			}
+			bases = append(bases, base)
+		}
+	}
+	return bases, nil
+}
+
+// holidayKeys lists the keys of a holiday in a holiday file.
+const holidayKeys = "name, date, observed, since, until or days"
+
+// parseHolidayRule parses a holiday of a holiday file.
+func parseHolidayRule(node *yaml.Node) (HolidayRule, error) {
+	var rule HolidayRule
+	if node.Kind != yaml.MappingNode {
+		return rule, errors.Errorf("line %d: a holiday must be a mapping of %s", node.Line, holidayKeys)
+	}
+	var date *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		s, err := scalarValue(value, key.Value)
+		if err != nil {
+			return rule, err
+		}
+		switch key.Value {
+		case "name":
+			rule.Name = strings.TrimSpace(s)
+		case "date":
+			date = value
+			if rule.Date, err = ParseDateRule(s); err != nil {
+				return rule, errors.Errorf("line %d: %s", value.Line, err)
+			}
+		case "observed":
+			if rule.Observed, err = ParseObservance(s); err != nil {
+				return rule, errors.Errorf("line %d: %s", value.Line, err)
+			}
+		case "since", "until", "days":
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return rule, errors.Errorf("line %d: %s must be a positive number, not %q", value.Line, key.Value, s)
+			}
+			switch key.Value {
+			case "since":
+				rule.Since = n
+			case "until":
+				rule.Until = n
+			default:
+				rule.Days = n
+			}
+		default:
+			return rule, errors.Errorf("line %d: unknown key %q, expected %s", key.Line, key.Value, holidayKeys)
+		}
+	}
+	switch {
+	case rule.Name == "":
+		return rule, errors.Errorf("line %d: holiday has no name", node.Line)
+	case date == nil:
+		return rule, errors.Errorf("line %d: holiday %q has no date", node.Line, rule.Name)
+	case rule.Since != 0 && rule.Until != 0 && rule.Since > rule.Until:
+		return rule, errors.Errorf("line %d: holiday %q ends in %d before it starts in %d", node.Line, rule.Name, rule.Until, rule.Since)
+	case rule.Days > 1 && rule.Observed != ObserveNone:
+		return rule, errors.Errorf("line %d: holiday %q lasts %d days and cannot be observed on another day", node.Line, rule.Name, rule.Days)
+	}
+	return rule, nil
+}
+
+// scalarValue returns the value of a scalar node, or an error naming key if
+// node is not a scalar.
+func scalarValue(node *yaml.Node, key string) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", errors.Errorf("line %d: %s must be a single value", node.Line, key)
+	}
+	return node.Value, nil
+}
+
+// listValue returns the elements of a sequence node, or the node itself if it
+// is a scalar.
+func listValue(node *yaml.Node, key string) ([]*yaml.Node, error) {
+	if node.Kind == yaml.ScalarNode {
+		return []*yaml.Node{node}, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, errors.Errorf("line %d: %s must be a list", node.Line, key)
+	}
+	for _, element := range node.Content {
+		if element.Kind != yaml.ScalarNode {
+			return nil, errors.Errorf("line %d: %s must be a list of values", element.Line, key)
+		}
+	}
+	return node.Content, nil
+}
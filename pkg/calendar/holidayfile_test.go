@@ -0,0 +1,262 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlHolidayFile = `name: Example Corp.
+base: us
+exclude: [Columbus Day]
+holidays:
+  - name: Founders' Day
+    date: jun 12
+    observed: nearest-weekday
+    since: 2015
+  - name: Summer shutdown
+    date: 1st mon of aug
+    days: 5
+  - name: Office move
+    date: 2026-03-13
+`
+
+const jsonHolidayFile = `{
+  "name": "Example Corp.",
+  "base": "us",
+  "exclude": ["Columbus Day"],
+  "holidays": [
+    {"name": "Founders' Day", "date": "jun 12", "observed": "nearest-weekday", "since": 2015},
+    {"name": "Summer shutdown", "date": "1st mon of aug", "days": 5},
+    {"name": "Office move", "date": "2026-03-13"}
+  ]
+}
+`
+
+const tomlHolidayFile = `# Company holidays
+name = "Example Corp."
+base = "us"
+exclude = [
+  "Columbus Day", # not observed
+]
+
+[[holidays]]
+name = "Founders' Day"
+date = "jun 12"
+observed = "nearest-weekday"
+since = 2015
+
+[[holidays]]
+name = 'Summer shutdown'
+date = "1st mon of aug"
+days = 5
+
+[[holidays]]
+name = "Office move"
+date = 2026-03-13
+`
+
+func TestParseHolidayFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{name: "YAML", file: "example.yaml", data: yamlHolidayFile},
+		{name: "JSON", file: "example.json", data: jsonHolidayFile},
+		{name: "TOML", file: "example.toml", data: tomlHolidayFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseHolidayFile(tt.file, []byte(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, "example", r.Code)
+			assert.Equal(t, "Example Corp.", r.Name)
+
+			dates := make(map[time.Time]string)
+			for _, h := range r.Holidays(2026) {
+				dates[h.Date] = h.Name
+			}
+			assert.Equal(t, "Independence Day", dates[date(2026, time.July, 4)])
+			assert.NotContains(t, dates, date(2026, time.October, 12), "Columbus Day is excluded")
+			assert.Equal(t, "Founders' Day", dates[date(2026, time.June, 12)])
+			for day := 3; day <= 7; day++ {
+				assert.Equal(t, "Summer shutdown", dates[date(2026, time.August, day)])
+			}
+			assert.NotContains(t, dates, date(2026, time.August, 8))
+			assert.Equal(t, "Office move", dates[date(2026, time.March, 13)])
+			assert.Len(t, r.Holidays(2026), 10+1+5+1)
+			assert.Len(t, r.Holidays(2027), 10+1+5)
+			assert.Len(t, r.Holidays(2014), 9+5, "Founders' Day since 2015, Juneteenth since 2021")
+		})
+	}
+}
+
+func TestParseHolidayFileSpansYears(t *testing.T) {
+	r, err := ParseHolidayFile("acme.yaml", []byte("code: ACME\nholidays:\n  - {name: Winter shutdown, date: 12-28, days: 7}\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ACME", r.Code)
+	assert.Equal(t, "ACME", r.Name)
+	var dates []time.Time
+	for _, h := range r.Holidays(2027) {
+		dates = append(dates, h.Date)
+	}
+	assert.Equal(t, []time.Time{
+		date(2027, time.January, 1), date(2027, time.January, 2), date(2027, time.January, 3),
+		date(2027, time.December, 28), date(2027, time.December, 29), date(2027, time.December, 30), date(2027, time.December, 31),
+	}, dates)
+}
+
+func TestParseHolidayFileMergesBases(t *testing.T) {
+	r, err := ParseHolidayFile("na.yaml", []byte("base: [us, ca]\n"))
+	assert.NoError(t, err)
+	var christmas int
+	for _, h := range r.Holidays(2026) {
+		if h.Name == "Christmas Day" {
+			christmas++
+		}
+	}
+	assert.Equal(t, 1, christmas, "holidays shared by the base regions are merged")
+}
+
+func TestParseHolidayFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "empty",
+			data:     "",
+			expected: "empty holiday file",
+		},
+		{
+			name:     "not a mapping",
+			data:     "- a\n- b\n",
+			expected: "line 1: a holiday file must be a mapping",
+		},
+		{
+			name:     "unknown key",
+			data:     "name: x\nregion: us\n",
+			expected: `line 2: unknown key "region", expected code, name, base, exclude or holidays`,
+		},
+		{
+			name:     "unknown base",
+			data:     "base: us, xx\n",
+			expected: `line 1: unknown holiday region "xx", expected one of CA, DE, GB, US`,
+		},
+		{
+			name:     "invalid date",
+			data:     "holidays:\n  - name: Company Day\n    date: 3rd fri of jum\n",
+			expected: `line 3: invalid date rule "3rd fri of jum"`,
+		},
+		{
+			name:     "invalid observance",
+			data:     "holidays:\n  - name: Company Day\n    date: 06-12\n    observed: monday\n",
+			expected: `line 4: invalid observance "monday", expected none, nearest-weekday or next-weekday`,
+		},
+		{
+			name:     "invalid days",
+			data:     "holidays:\n  - name: Shutdown\n    date: 12-24\n    days: 0\n",
+			expected: `line 4: days must be a positive number, not "0"`,
+		},
+		{
+			name:     "unknown holiday key",
+			data:     "holidays:\n  - name: Company Day\n    when: 06-12\n",
+			expected: `line 3: unknown key "when", expected name, date, observed, since, until or days`,
+		},
+		{
+			name:     "missing name",
+			data:     "holidays:\n  - date: 06-12\n",
+			expected: "line 2: holiday has no name",
+		},
+		{
+			name:     "missing date",
+			data:     "holidays:\n  - name: Company Day\n",
+			expected: `line 2: holiday "Company Day" has no date`,
+		},
+		{
+			name:     "since after until",
+			data:     "holidays:\n  - {name: Company Day, date: 06-12, since: 2030, until: 2020}\n",
+			expected: `line 2: holiday "Company Day" ends in 2020 before it starts in 2030`,
+		},
+		{
+			name:     "observed span",
+			data:     "holidays:\n  - {name: Shutdown, date: 12-24, days: 8, observed: next-weekday}\n",
+			expected: `line 2: holiday "Shutdown" lasts 8 days and cannot be observed on another day`,
+		},
+		{
+			name:     "duplicate name",
+			data:     "holidays:\n  - {name: Company Day, date: 06-12}\n  - {name: Company Day, date: 06-13}\n",
+			expected: `line 3: holiday "Company Day" is already defined on line 2`,
+		},
+		{
+			name:     "duplicate date",
+			data:     "holidays:\n  - {name: Company Day, date: 06-12}\n  - {name: Founders' Day, date: jun 12}\n",
+			expected: `line 3: holiday "Founders' Day" falls on the same date as "Company Day" on line 2`,
+		},
+		{
+			name:     "same date in other years",
+			data:     "holidays:\n  - {name: Company Day, date: 06-12, until: 2020}\n  - {name: Founders' Day, date: jun 12, since: 2021}\n  - {name: Company Day, date: 07-01}\n",
+			expected: `line 4: holiday "Company Day" is already defined on line 2`,
+		},
+		{
+			name:     "name of a base holiday",
+			data:     "base: us\nholidays:\n  - {name: Labor Day, date: 05-01}\n",
+			expected: `line 3: holiday "Labor Day" is already defined in region US, exclude it to replace it`,
+		},
+		{
+			name:     "date of a base holiday",
+			data:     "base: gb\nholidays:\n  - {name: Christmas, date: dec 25}\n",
+			expected: `line 3: holiday "Christmas" falls on the same date as "Christmas Day" in region GB, exclude it to replace it`,
+		},
+		{
+			name:     "exclude unknown holiday",
+			data:     "base: us\nexclude:\n  - Boxing Day\n",
+			expected: `line 3: no holiday "Boxing Day" in US`,
+		},
+		{
+			name:     "exclude without base",
+			data:     "exclude: Columbus Day\n",
+			expected: `line 1: cannot exclude "Columbus Day" without a base region`,
+		},
+		{
+			name:     "TOML duplicate key",
+			file:     "x.toml",
+			data:     "name = \"a\"\nname = \"b\"\n",
+			expected: "invalid holiday file: line 2: key name is already defined",
+		},
+		{
+			name:     "TOML syntax error",
+			file:     "x.toml",
+			data:     "name = \"a\"\nbase = [\"us\"\n",
+			expected: "invalid holiday file: line 2: array is incomplete",
+		},
+		{
+			name:     "TOML invalid date",
+			file:     "x.toml",
+			data:     "[[holidays]]\nname = \"Company Day\"\ndate = \"jun 31\"\n",
+			expected: `line 3: invalid date rule "jun 31"`,
+		},
+		{
+			name:     "TOML inline table",
+			file:     "x.toml",
+			data:     "holidays = [\n  {name = \"Company Day\", date = \"jun 31\"},\n]\n",
+			expected: `line 2: invalid date rule "jun 31"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := tt.file
+			if file == "" {
+				file = "holidays.yaml"
+			}
+			_, err := ParseHolidayFile(file, []byte(tt.data))
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package calendar
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Locale holds the translated names used in calendar output.
+type Locale struct {
+	// Name is the language code of the locale, e.g. "de".
+	Name string
+	// Months holds the stand-alone (nominative) month names, used in
+	// calendar titles.
+	Months [12]string
+	// MonthsGenitive holds the month names as they appear inside a date,
+	// such as the genitive "октября" in "17 октября 2026". Locales that do
+	// not inflect month names leave it empty.
+	MonthsGenitive [12]string
+	// Weekdays holds the weekday abbreviations, Sunday first. They fit into
+	// the two columns of a day cell.
+	Weekdays [7]string
+	// TitleFormat lays out the title of a month. "{month}" is replaced by the
+	// stand-alone month name and "{year}" by the year.
+	TitleFormat string
+	// YearFormat lays out the banner over the calendar of a year. "{year}"
+	// is replaced by the year. An empty format shows just the year.
+	YearFormat string
+	// DateFormat lays out a full date. "{day}" is replaced by the day of
+	// the month, "{month}" by the month name as it appears inside a date and
+	// "{year}" by the year.
+	DateFormat string
+}
+
+// MonthName returns the stand-alone name of month.
+func (l *Locale) MonthName(month time.Month) string {
+	return l.Months[month-1]
+}
+
+// MonthNameGenitive returns the name of month as it appears inside a date,
+// falling back to the stand-alone name.
+func (l *Locale) MonthNameGenitive(month time.Month) string {
+	if name := l.MonthsGenitive[month-1]; name != "" {
+		return name
+	}
+	return l.MonthName(month)
+}
+
+// WeekdayAbbreviation returns the abbreviated name of weekday.
+func (l *Locale) WeekdayAbbreviation(weekday time.Weekday) string {
+	return l.Weekdays[weekday]
+}
+
+// Title returns the title of a month, e.g. "October 2026".
+func (l *Locale) Title(month time.Month, year int) string {
+	return strings.NewReplacer(
+		"{month}", l.MonthName(month),
+		"{year}", strconv.Itoa(year),
+	).Replace(l.TitleFormat)
+}
+
+// monthTitle returns the title of m in a calendar of a single month or a
+// span of months.
+func (l *Locale) monthTitle(m *Month) string {
+	return l.Title(m.Month, m.Year)
+}
+
+// YearTitle returns the banner over the calendar of a year, e.g. "2026".
+func (l *Locale) YearTitle(year int) string {
+	if l.YearFormat == "" {
+		return strconv.Itoa(year)
+	}
+	return strings.ReplaceAll(l.YearFormat, "{year}", strconv.Itoa(year))
+}
+
+// FormatDate formats the day, month and year of t, e.g. "October 17, 2026".
+func (l *Locale) FormatDate(t time.Time) string {
+	return strings.NewReplacer(
+		"{day}", strconv.Itoa(t.Day()),
+		"{month}", l.MonthNameGenitive(t.Month()),
+		"{year}", strconv.Itoa(t.Year()),
+	).Replace(l.DateFormat)
+}
+
+// English is the default locale.
+var English = &Locale{
+	Name:        "en",
+	Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	Weekdays:    [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	TitleFormat: "{month} {year}",
+	DateFormat:  "{month} {day}, {year}",
+}
+
+// locales maps language codes to the built-in locales.
+var locales = map[string]*Locale{
+	"en": English,
+	"cs": {
+		Name:           "cs",
+		Months:         [12]string{"leden", "únor", "březen", "duben", "květen", "červen", "červenec", "srpen", "září", "říjen", "listopad", "prosinec"},
+		MonthsGenitive: [12]string{"ledna", "února", "března", "dubna", "května", "června", "července", "srpna", "září", "října", "listopadu", "prosince"},
+		Weekdays:       [7]string{"ne", "po", "út", "st", "čt", "pá", "so"},
+		TitleFormat:    "{month} {year}",
+		DateFormat:     "{day}. {month} {year}",
+	},
+	"de": {
+		Name:        "de",
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		Weekdays:    [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day}. {month} {year}",
+	},
+	"es": {
+		Name:        "es",
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		Weekdays:    [7]string{"do", "lu", "ma", "mi", "ju", "vi", "sá"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day} de {month} de {year}",
+	},
+	"fr": {
+		Name:        "fr",
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		Weekdays:    [7]string{"di", "lu", "ma", "me", "je", "ve", "sa"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day} {month} {year}",
+	},
+	"it": {
+		Name:        "it",
+		Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		Weekdays:    [7]string{"do", "lu", "ma", "me", "gi", "ve", "sa"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day} {month} {year}",
+	},
+	"ja": {
+		Name:        "ja",
+		Months:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		Weekdays:    [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		TitleFormat: "{year}年{month}",
+		YearFormat:  "{year}年",
+		DateFormat:  "{year}年{month}{day}日",
+	},
+	"ko": {
+		Name:        "ko",
+		Months:      [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		Weekdays:    [7]string{"일", "월", "화", "수", "목", "금", "토"},
+		TitleFormat: "{year}년 {month}",
+		YearFormat:  "{year}년",
+		DateFormat:  "{year}년 {month} {day}일",
+	},
+	"nl": {
+		Name:        "nl",
+		Months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		Weekdays:    [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day} {month} {year}",
+	},
+	"pl": {
+		Name:           "pl",
+		Months:         [12]string{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec", "lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień"},
+		MonthsGenitive: [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+		Weekdays:       [7]string{"nd", "pn", "wt", "śr", "cz", "pt", "so"},
+		TitleFormat:    "{month} {year}",
+		DateFormat:     "{day} {month} {year}",
+	},
+	"pt": {
+		Name:        "pt",
+		Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		Weekdays:    [7]string{"do", "se", "te", "qu", "qu", "se", "sá"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day} de {month} de {year}",
+	},
+	"ru": {
+		Name:           "ru",
+		Months:         [12]string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь", "Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"},
+		MonthsGenitive: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		Weekdays:       [7]string{"Вс", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"},
+		TitleFormat:    "{month} {year}",
+		DateFormat:     "{day} {month} {year}",
+	},
+	"sv": {
+		Name:        "sv",
+		Months:      [12]string{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+		Weekdays:    [7]string{"sö", "må", "ti", "on", "to", "fr", "lö"},
+		TitleFormat: "{month} {year}",
+		DateFormat:  "{day} {month} {year}",
+	},
+	"uk": {
+		Name:           "uk",
+		Months:         [12]string{"Січень", "Лютий", "Березень", "Квітень", "Травень", "Червень", "Липень", "Серпень", "Вересень", "Жовтень", "Листопад", "Грудень"},
+		MonthsGenitive: [12]string{"січня", "лютого", "березня", "квітня", "травня", "червня", "липня", "серпня", "вересня", "жовтня", "листопада", "грудня"},
+		Weekdays:       [7]string{"Нд", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"},
+		TitleFormat:    "{month} {year}",
+		DateFormat:     "{day} {month} {year}",
+	},
+	"zh": {
+		Name:           "zh",
+		Months:         [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		MonthsGenitive: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		Weekdays:       [7]string{"日", "一", "二", "三", "四", "五", "六"},
+		TitleFormat:    "{month} {year}",
+		YearFormat:     "{year}年",
+		DateFormat:     "{year}年{month}{day}日",
+	},
+}
+
+// Locales returns the names of the built-in locales in alphabetical order.
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupLocale returns the built-in locale for a POSIX locale name such as
+// "de_DE.UTF-8" or a language tag such as "pt-BR". Only the language is
+// significant. "C" and "POSIX" select English.
+func LookupLocale(name string) (*Locale, error) {
+	lang := name
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	lang = strings.ToLower(lang)
+	if lang == "c" || lang == "posix" {
+		return English, nil
+	}
+	if l, ok := locales[lang]; ok {
+		return l, nil
+	}
+	return nil, errors.Errorf("unsupported locale %q", name)
+}
+
+// LocaleFromEnv returns the locale selected by the LC_ALL, LC_TIME and LANG
+// environment variables, in that order of precedence. It falls back to
+// English when none of them names a supported locale.
+func LocaleFromEnv() *Locale {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		if l, err := LookupLocale(value); err == nil {
+			return l
+		}
+		return English
+	}
+	return English
+}
@@ -0,0 +1,120 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "POSIX locale name", input: "de_DE.UTF-8", expected: "de"},
+		{name: "language tag", input: "pt-BR", expected: "pt"},
+		{name: "language only", input: "ru", expected: "ru"},
+		{name: "modifier", input: "uk_UA@euro", expected: "uk"},
+		{name: "C locale", input: "C.UTF-8", expected: "en"},
+		{name: "POSIX", input: "POSIX", expected: "en"},
+		{name: "unsupported", input: "tlh_QO", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := LookupLocale(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, l.Name, "LookupLocale should return the expected locale")
+		})
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{name: "nothing set", env: map[string]string{}, expected: "en"},
+		{name: "LANG", env: map[string]string{"LANG": "fr_FR.UTF-8"}, expected: "fr"},
+		{name: "LC_TIME overrides LANG", env: map[string]string{"LANG": "fr_FR.UTF-8", "LC_TIME": "sv_SE.UTF-8"}, expected: "sv"},
+		{name: "LC_ALL overrides LC_TIME", env: map[string]string{"LC_TIME": "sv_SE.UTF-8", "LC_ALL": "pl_PL.UTF-8"}, expected: "pl"},
+		{name: "unsupported falls back to English", env: map[string]string{"LANG": "tlh_QO"}, expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+				t.Setenv(key, tt.env[key])
+			}
+			assert.Equal(t, tt.expected, LocaleFromEnv().Name, "LocaleFromEnv should honor the locale variables")
+		})
+	}
+}
+
+func TestLocaleNames(t *testing.T) {
+	ru, err := LookupLocale("ru")
+	assert.NoError(t, err)
+	assert.Equal(t, "Октябрь 2026", ru.Title(time.October, 2026), "titles use the nominative")
+	assert.Equal(t, "17 октября 2026", ru.FormatDate(time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)), "dates use the genitive")
+
+	de, err := LookupLocale("de")
+	assert.NoError(t, err)
+	assert.Equal(t, "März", de.MonthNameGenitive(time.March), "locales without genitive fall back to the nominative")
+	assert.Equal(t, "17. Oktober 2026", de.FormatDate(time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)))
+
+	ja, err := LookupLocale("ja")
+	assert.NoError(t, err)
+	assert.Equal(t, "2026年10月", ja.Title(time.October, 2026))
+	assert.Equal(t, "2026年", ja.YearTitle(2026))
+	assert.Equal(t, "2026", de.YearTitle(2026), "locales without a year format show the bare year")
+
+	assert.Equal(t, "October 17, 2026", English.FormatDate(time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestLocalizedMonthCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		expected string
+	}{
+		{
+			name:   "German",
+			locale: "de",
+			expected: "     März 2024      \n" +
+				"Mo Di Mi Do Fr Sa So\n" +
+				"             1  2  3 \n" +
+				" 4  5  6  7  8  9 10 \n" +
+				"11 12 13 14 15 16 17 \n" +
+				"18 19 20 21 22 23 24 \n" +
+				"25 26 27 28 29 30 31 \n\n",
+		},
+		{
+			name:   "Russian",
+			locale: "ru",
+			expected: "     Март 2024      \n" +
+				"Пн Вт Ср Чт Пт Сб Вс\n" +
+				"             1  2  3 \n" +
+				" 4  5  6  7  8  9 10 \n" +
+				"11 12 13 14 15 16 17 \n" +
+				"18 19 20 21 22 23 24 \n" +
+				"25 26 27 28 29 30 31 \n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := LookupLocale(tt.locale)
+			assert.NoError(t, err)
+			result := stripAnsiCodes(buildMonthCalendar(time.March, 2024, WithLocale(l), WithFirstWeekday(time.Monday)))
+			assert.Equal(t, tt.expected, result, "Calendar output should use the locale's names")
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"time"
+)
+
+// Day is a single day of a Month.
+type Day struct {
+	// Date is the day at midnight UTC in the proleptic Gregorian calendar of
+	// package time. Before the calendar reform it differs from Year, Month
+	// and Day, which are written in the Julian calendar.
+	Date time.Time
+	// Year, Month and Day are the date as written in the calendar in use.
+	Year  int
+	Month time.Month
+	Day   int
+	// Weekday is the day of the week.
+	Weekday time.Weekday
+	// YearDay is the day of the year, starting at 1.
+	YearDay int
+	// ISOYear and ISOWeek are the ISO 8601 year and week number of Date.
+	ISOYear int
+	ISOWeek int
+	// Today is set for the current day, see WithToday.
+	Today bool
+	// Weekend is set for the weekend days, Saturday and Sunday unless
+	// WithWeekend says otherwise.
+	Weekend bool
+	// Holiday is set for public holidays and the days they are observed on,
+	// see WithHolidays. Days in the Julian calendar of the reform are never
+	// holidays, as the rules only hold in the Gregorian calendar.
+	Holiday bool
+	// Outside is set for the days of adjacent months, see WithOutsideDays.
+	Outside bool
+	// Events lists the events on the day, see WithEvents.
+	Events []*Event
+}
+
+// Week is a row of a Month.
+type Week struct {
+	// Number is the week number selected by WithWeekNumbers, or 0 when week
+	// numbers are disabled.
+	Number int
+	// Days holds the days of the week in column order, starting with the
+	// first weekday. Columns falling outside the month are nil, or hold days
+	// of the adjacent months with WithOutsideDays.
+	Days [7]*Day
+}
+
+// Month is the layout of a month as rows of weeks.
+type Month struct {
+	Year         int
+	Month        time.Month
+	FirstWeekday time.Weekday
+	Weeks        []Week
+}
+
+// NewMonth lays out a month. The options select the first weekday, the week
+// numbers and the calendar reform.
+func NewMonth(month time.Month, year int, opts ...Option) *Month {
+	return newMonth(month, year, newOptions(opts))
+}
+
+// newMonth lays out a month with resolved options.
+func newMonth(month time.Month, year int, o *options) *Month {
+	m := &Month{
+		Year:         year,
+		Month:        month,
+		FirstWeekday: o.firstWeekday,
+	}
+
+	for i, rd := range o.reform.monthDays(month, year) {
+		column := weekdayColumn(rd.weekday, o.firstWeekday)
+		if i == 0 || column == 0 {
+			rowStart := rd.date.AddDate(0, 0, -column)
+			m.Weeks = append(m.Weeks, Week{Number: rowWeekNumber(o.weekNumbers, rowStart, rd.date)})
+		}
+		m.Weeks[len(m.Weeks)-1].Days[column] = newDay(rd, month, year, o)
+	}
+	if o.outsideDays {
+		m.fillOutsideDays(o)
+	}
+	return m
+}
+
+// newDay returns the day rd of the given month.
+func newDay(rd reformDay, month time.Month, year int, o *options) *Day {
+	todayYear, todayMonth, todayDay := o.today.Date()
+	isoYear, isoWeek := rd.date.ISOWeek()
+	dateYear, dateMonth, dateDay := rd.date.Date()
+	return &Day{
+		Date:    rd.date,
+		Year:    year,
+		Month:   month,
+		Day:     rd.day,
+		Weekday: rd.weekday,
+		YearDay: rd.yearDay,
+		ISOYear: isoYear,
+		ISOWeek: isoWeek,
+		Today:   dateYear == todayYear && dateMonth == todayMonth && dateDay == todayDay,
+		Weekend: o.weekend[rd.weekday],
+		Holiday: !o.reform.isJulian(civilDate{year, month, rd.day}) && o.holidays.contains(rd.date),
+		Events:  o.events.on(rd.date),
+	}
+}
+
+// fillOutsideDays fills the empty cells of the first and the last week with
+// the last days of the previous month and the first days of the next month.
+func (m *Month) fillOutsideDays(o *options) {
+	first, last := &m.Weeks[0], &m.Weeks[len(m.Weeks)-1]
+
+	prev := yearMonth{year: m.Year, month: m.Month}.addMonths(-1)
+	before := o.reform.monthDays(prev.month, prev.year)
+	leading := 0
+	for leading < 7 && first.Days[leading] == nil {
+		leading++
+	}
+	for column := range leading {
+		day := newDay(before[len(before)-leading+column], prev.month, prev.year, o)
+		day.Outside = true
+		first.Days[column] = day
+	}
+
+	next := yearMonth{year: m.Year, month: m.Month}.addMonths(1)
+	after := o.reform.monthDays(next.month, next.year)
+	trailing := 0
+	for trailing < 7 && last.Days[6-trailing] == nil {
+		trailing++
+	}
+	for i := range trailing {
+		day := newDay(after[i], next.month, next.year, o)
+		day.Outside = true
+		last.Days[7-trailing+i] = day
+	}
+}
+
+// Weekdays returns the weekdays of the columns, starting with the first
+// weekday.
+func (m *Month) Weekdays() [7]time.Weekday {
+	var weekdays [7]time.Weekday
+	for i := range weekdays {
+		weekdays[i] = (m.FirstWeekday + time.Weekday(i)) % 7
+	}
+	return weekdays
+}
+
+// Days returns the days of the month in order, leaving out the days of
+// adjacent months.
+func (m *Month) Days() []*Day {
+	var days []*Day
+	for _, week := range m.Weeks {
+		for _, day := range week.Days {
+			if day != nil && !day.Outside {
+				days = append(days, day)
+			}
+		}
+	}
+	return days
+}
@@ -0,0 +1,119 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMonth(t *testing.T) {
+	today := time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)
+	m := NewMonth(time.February, 2024, WithFirstWeekday(time.Monday), WithWeekNumbers(ISOWeekNumbers), WithToday(today))
+
+	assert.Equal(t, 2024, m.Year)
+	assert.Equal(t, time.February, m.Month)
+	assert.Equal(t, [7]time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}, m.Weekdays())
+	require.Len(t, m.Weeks, 5)
+
+	first := m.Weeks[0]
+	assert.Equal(t, 5, first.Number)
+	assert.Nil(t, first.Days[0], "January 29 is outside the month")
+	assert.Nil(t, first.Days[2], "January 31 is outside the month")
+	require.NotNil(t, first.Days[3])
+	assert.Equal(t, &Day{
+		Date:    time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		Year:    2024,
+		Month:   time.February,
+		Day:     1,
+		Weekday: time.Thursday,
+		YearDay: 32,
+		ISOYear: 2024,
+		ISOWeek: 5,
+	}, first.Days[3])
+	assert.True(t, m.Weeks[1].Days[5].Today, "February 10 is today")
+	assert.True(t, first.Days[5].Weekend, "Saturday is a weekend day")
+	assert.False(t, first.Days[4].Weekend, "Friday is not a weekend day")
+
+	last := m.Weeks[4]
+	assert.Equal(t, 9, last.Number)
+	assert.Equal(t, 29, last.Days[3].Day)
+	assert.Nil(t, last.Days[4], "March 1 is outside the month")
+
+	days := m.Days()
+	require.Len(t, days, 29)
+	for i, day := range days {
+		assert.Equal(t, i+1, day.Day, "Days should return the days in order")
+	}
+}
+
+func TestNewMonthReform(t *testing.T) {
+	m := NewMonth(time.September, 1752, WithReform(Reform1752))
+	days := m.Days()
+	require.Len(t, days, 19)
+
+	assert.Equal(t, 2, days[1].Day)
+	assert.Equal(t, time.Date(1752, time.September, 13, 0, 0, 0, 0, time.UTC), days[1].Date, "dates before the reform are Julian")
+	assert.Equal(t, 14, days[2].Day)
+	assert.Equal(t, 258, days[2].YearDay)
+	assert.Same(t, days[2], m.Weeks[0].Days[4], "September 14 follows September 2 in the same week")
+}
+
+func TestNewMonthToday(t *testing.T) {
+	tests := []struct {
+		name     string
+		today    time.Time
+		expected int
+	}{
+		{name: "today in the month", today: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), expected: 29},
+		{name: "time of day and location are ignored", today: time.Date(2024, time.February, 3, 23, 59, 0, 0, time.FixedZone("UTC-10", -10*3600)), expected: 3},
+		{name: "today in another month", today: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonth(time.February, 2024, WithToday(tt.today))
+			highlighted := 0
+			for _, day := range m.Days() {
+				if day.Today {
+					assert.Zero(t, highlighted, "only one day should be today")
+					highlighted = day.Day
+				}
+			}
+			assert.Equal(t, tt.expected, highlighted)
+		})
+	}
+}
+
+func TestNewMonthTodayDefaultsToNow(t *testing.T) {
+	now := time.Now()
+	m := NewMonth(now.Month(), now.Year())
+	today := 0
+	for _, day := range m.Days() {
+		if day.Today {
+			today++
+		}
+	}
+	assert.Equal(t, 1, today, "the current day should be highlighted by default")
+}
+
+func TestNewMonthOutsideDays(t *testing.T) {
+	m := NewMonth(time.January, 2025, WithFirstWeekday(time.Monday), WithOutsideDays(true))
+
+	first := m.Weeks[0].Days
+	assert.Equal(t, 30, first[0].Day)
+	assert.Equal(t, time.December, first[0].Month)
+	assert.Equal(t, 2024, first[0].Year, "the previous month may be in the previous year")
+	assert.True(t, first[1].Outside)
+	assert.False(t, first[2].Outside, "January 1 is a Wednesday")
+
+	last := m.Weeks[len(m.Weeks)-1].Days
+	assert.Equal(t, 31, last[4].Day)
+	assert.Equal(t, 2, last[6].Day)
+	assert.Equal(t, time.February, last[6].Month)
+	assert.True(t, last[6].Outside)
+
+	assert.Len(t, m.Days(), 31, "Days leaves out the days of adjacent months")
+	assert.Nil(t, NewMonth(time.January, 2025, WithFirstWeekday(time.Monday)).Weeks[0].Days[0], "outside days are off by default")
+}
@@ -0,0 +1,278 @@
+package calendar
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Option configures how a calendar is laid out.
+type Option func(*options)
+
+// options holds the settings shared by all renderers.
+type options struct {
+	firstWeekday time.Weekday
+	weekNumbers  WeekNumbering
+	dayOfYear    bool
+	reform       Reform
+	locale       *Locale
+	today        time.Time
+	monthsPerRow int
+	layout       Layout
+	color        ColorMode
+	theme        *Theme
+	outsideDays  bool
+	weekend      [7]bool
+	holidays     *holidaySet
+	events       *eventSet
+	eventMarker  rune
+	eventLegend  bool
+
+	// colorize is whether highlighting is enabled for the output at hand,
+	// as decided by color.
+	colorize bool
+}
+
+// newOptions applies opts on top of the defaults.
+func newOptions(opts []Option) *options {
+	o := &options{
+		firstWeekday: time.Sunday,
+		reform:       ReformGregorian,
+		locale:       English,
+		today:        time.Now(),
+		monthsPerRow: 3,
+		theme:        DefaultTheme,
+		eventMarker:  defaultEventMarker,
+	}
+	o.weekend[time.Saturday], o.weekend[time.Sunday] = true, true
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.colorize = o.color == ColorAlways
+	return o
+}
+
+// newWriterOptions applies opts like newOptions and decides whether output
+// written to w is highlighted.
+func newWriterOptions(w io.Writer, opts []Option) *options {
+	o := newOptions(opts)
+	o.colorize = o.color.Enabled(w)
+	return o
+}
+
+// WithFirstWeekday sets the weekday shown in the leftmost column. The default
+// is time.Sunday; use time.Monday for ISO 8601 style calendars.
+func WithFirstWeekday(weekday time.Weekday) Option {
+	return func(o *options) {
+		o.firstWeekday = weekday % 7
+	}
+}
+
+// WithWeekNumbers prefixes every week with its week number.
+func WithWeekNumbers(numbering WeekNumbering) Option {
+	return func(o *options) {
+		o.weekNumbers = numbering
+	}
+}
+
+// WithDayOfYear numbers days by their day of the year (1-366) instead of their
+// day of the month, like "cal -j".
+func WithDayOfYear(enabled bool) Option {
+	return func(o *options) {
+		o.dayOfYear = enabled
+	}
+}
+
+// WithReform selects when the Julian calendar was replaced by the Gregorian
+// calendar. The default is ReformGregorian, which uses the Gregorian calendar
+// for all dates; Reform1752 matches the traditional UNIX cal.
+func WithReform(r Reform) Option {
+	return func(o *options) {
+		o.reform = r
+	}
+}
+
+// WithLocale selects the language of month and weekday names. The default is
+// English.
+func WithLocale(l *Locale) Option {
+	return func(o *options) {
+		if l != nil {
+			o.locale = l
+		}
+	}
+}
+
+// WithToday sets the date that is highlighted as today. Only the year, month
+// and day of t are used. The default is the current date.
+func WithToday(t time.Time) Option {
+	return func(o *options) {
+		o.today = t
+	}
+}
+
+// WithMonthsPerRow sets how many months RenderMonths and RenderYear lay out
+// side by side. The default is 3; see FitMonthsPerRow for choosing a number
+// that suits the terminal.
+func WithMonthsPerRow(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.monthsPerRow = n
+		}
+	}
+}
+
+// WithLayout selects the horizontal layout of cal or the vertical layout of
+// ncal. The default is LayoutHorizontal.
+func WithLayout(layout Layout) Option {
+	return func(o *options) {
+		o.layout = layout
+	}
+}
+
+// WithColor selects whether calendars are highlighted with ANSI escape
+// sequences. The default is ColorAuto, which highlights only output written
+// to a terminal. Calendars returned as strings are only highlighted with
+// ColorAlways.
+func WithColor(mode ColorMode) Option {
+	return func(o *options) {
+		o.color = mode
+	}
+}
+
+// WithTheme sets the styles used for highlighting. The default is
+// DefaultTheme.
+func WithTheme(t *Theme) Option {
+	return func(o *options) {
+		if t != nil {
+			o.theme = t
+		}
+	}
+}
+
+// WithOutsideDays fills the cells before the first and after the last day of
+// a month with the days of the adjacent months.
+func WithOutsideDays(enabled bool) Option {
+	return func(o *options) {
+		o.outsideDays = enabled
+	}
+}
+
+// WithWeekend sets the days of the week that are not working days, e.g.
+// time.Friday and time.Saturday in much of the Middle East. The default is
+// Saturday and Sunday; no days means no weekend.
+func WithWeekend(days ...time.Weekday) Option {
+	return func(o *options) {
+		o.weekend = [7]bool{}
+		for _, d := range days {
+			o.weekend[d%7] = true
+		}
+	}
+}
+
+// WithHolidays marks the holidays of the given regions, and the days they
+// are observed on, as holidays. See LookupRegion for the built-in regions.
+func WithHolidays(regions ...*Region) Option {
+	return func(o *options) {
+		o.holidays = &holidaySet{regions: regions, years: make(map[int]map[time.Time]bool)}
+	}
+}
+
+// cellWidth returns the width of a single day, without the separating space.
+func (o *options) cellWidth() int {
+	if o.dayOfYear {
+		return 3
+	}
+	return 2
+}
+
+// daysWidth returns the width of the seven day columns.
+func (o *options) daysWidth() int {
+	return 7*o.cellWidth() + 6
+}
+
+// weekNumberWidth returns the width of the week number column, including
+// the space separating it from the days.
+func (o *options) weekNumberWidth() int {
+	if o.weekNumbers == NoWeekNumbers {
+		return 0
+	}
+	return 3
+}
+
+// monthWidth returns the width of a rendered month, week numbers included.
+func (o *options) monthWidth() int {
+	return o.weekNumberWidth() + o.daysWidth()
+}
+
+// monthGap is the number of spaces between months laid out side by side.
+const monthGap = 4
+
+// rowWidth returns the width of n months laid out side by side.
+func (o *options) rowWidth(n int) int {
+	if o.layout == LayoutVertical {
+		return verticalLabelWidth + n*o.verticalBlockWidth(verticalWeeks) + (n-1)*verticalGap
+	}
+	return n*o.monthWidth() + (n-1)*monthGap
+}
+
+// FitMonthsPerRow returns how many of count months to lay out side by side
+// so that they fit into width terminal columns. It prefers a number that
+// divides count evenly, so that a year is shown as 4×3, 6×2 or 1×12 months
+// rather than leaving a ragged last row, unless only one month per row would
+// remain. At least one month per row is returned.
+func FitMonthsPerRow(width, count int, opts ...Option) int {
+	o := newOptions(opts)
+	fit := 1
+	for n := count; n > 1; n-- {
+		if o.rowWidth(n) <= width {
+			fit = n
+			break
+		}
+	}
+	for n := fit; n > 1; n-- {
+		if count%n == 0 {
+			return n
+		}
+	}
+	return fit
+}
+
+// ParseWeekday parses an English weekday name, which may be abbreviated to two
+// or more letters, or a number from 0 (Sunday) to 7 (Sunday again, as in ISO
+// 8601 where Monday is 1).
+func ParseWeekday(s string) (time.Weekday, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 7 {
+			return 0, errors.Errorf("weekday %d not in range 0..7", n)
+		}
+		return time.Weekday(n % 7), nil
+	}
+	if len(s) >= 2 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), strings.ToLower(s)) {
+				return d, nil
+			}
+		}
+	}
+	return 0, errors.Errorf("invalid weekday %q", s)
+}
+
+// ParseWeekend parses a comma-separated list of weekdays as accepted by
+// ParseWeekday, such as "fri,sat". "none" is the empty list.
+func ParseWeekend(s string) ([]time.Weekday, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "none") {
+		return nil, nil
+	}
+	var days []time.Weekday
+	for _, field := range strings.Split(s, ",") {
+		d, err := ParseWeekday(strings.TrimSpace(field))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid weekend")
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
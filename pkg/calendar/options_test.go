@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Weekday
+		wantErr  bool
+	}{
+		{name: "full name", input: "Monday", expected: time.Monday},
+		{name: "abbreviation", input: "sa", expected: time.Saturday},
+		{name: "mixed case prefix", input: "THU", expected: time.Thursday},
+		{name: "zero is Sunday", input: "0", expected: time.Sunday},
+		{name: "seven is Sunday", input: "7", expected: time.Sunday},
+		{name: "ISO Monday", input: "1", expected: time.Monday},
+		{name: "ambiguous single letter", input: "t", wantErr: true},
+		{name: "out of range", input: "8", wantErr: true},
+		{name: "unknown name", input: "someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseWeekday(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result, "ParseWeekday should return the expected weekday")
+		})
+	}
+}
+
+func TestParseWeekend(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []time.Weekday
+		wantErr  bool
+	}{
+		{name: "Middle East", input: "fri,sat", expected: []time.Weekday{time.Friday, time.Saturday}},
+		{name: "spaces and numbers", input: "Sat, 7", expected: []time.Weekday{time.Saturday, time.Sunday}},
+		{name: "single day", input: "sun", expected: []time.Weekday{time.Sunday}},
+		{name: "none", input: "None", expected: nil},
+		{name: "empty element", input: "sat,", wantErr: true},
+		{name: "unknown day", input: "sat,someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseWeekend(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestWithWeekend(t *testing.T) {
+	weekendDays := func(opts ...Option) []time.Weekday {
+		var days []time.Weekday
+		for _, day := range NewMonth(time.October, 2026, opts...).Weeks[1].Days {
+			if day.Weekend {
+				days = append(days, day.Weekday)
+			}
+		}
+		return days
+	}
+
+	assert.Equal(t, []time.Weekday{time.Sunday, time.Saturday}, weekendDays(), "the default weekend is Saturday and Sunday")
+	assert.Equal(t, []time.Weekday{time.Friday, time.Saturday}, weekendDays(WithWeekend(time.Friday, time.Saturday)))
+	assert.Equal(t, []time.Weekday{time.Sunday}, weekendDays(WithWeekend(time.Sunday)))
+	assert.Empty(t, weekendDays(WithWeekend()))
+}
+
+func TestFitMonthsPerRow(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		count    int
+		opts     []Option
+		expected int
+	}{
+		{name: "classic 80 columns", width: 80, count: 12, expected: 3},
+		{name: "exactly three months", width: 68, count: 12, expected: 3},
+		{name: "just short of three months", width: 67, count: 12, expected: 2},
+		{name: "four months fit", width: 100, count: 12, expected: 4},
+		{name: "five months fit, four divide the year", width: 116, count: 12, expected: 4},
+		{name: "wide monitor", width: 200, count: 12, expected: 6},
+		{name: "very wide monitor", width: 300, count: 12, expected: 12},
+		{name: "narrow pane", width: 30, count: 12, expected: 1},
+		{name: "narrower than a month", width: 10, count: 12, expected: 1},
+		{name: "prime count keeps the fitting number", width: 80, count: 5, expected: 3},
+		{name: "fewer months than fit", width: 200, count: 3, expected: 3},
+		{name: "wider months", width: 80, count: 12, opts: []Option{WithDayOfYear(true), WithWeekNumbers(ISOWeekNumbers)}, expected: 2},
+		{name: "vertical layout", width: 80, count: 12, opts: []Option{WithLayout(LayoutVertical)}, expected: 4},
+		{name: "vertical layout on a wide monitor", width: 160, count: 12, opts: []Option{WithLayout(LayoutVertical)}, expected: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FitMonthsPerRow(tt.width, tt.count, tt.opts...)
+			assert.Equal(t, tt.expected, result, "FitMonthsPerRow should return the expected number of months")
+		})
+	}
+}
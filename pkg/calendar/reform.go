@@ -0,0 +1,261 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// civilDate is a date as written in a particular calendar.
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// before reports whether d comes before other when both are compared as
+// written, ignoring which calendar they belong to.
+func (d civilDate) before(other civilDate) bool {
+	if d.year != other.year {
+		return d.year < other.year
+	}
+	if d.month != other.month {
+		return d.month < other.month
+	}
+	return d.day < other.day
+}
+
+// Reform describes the switch from the Julian to the Gregorian calendar.
+// Dates up to and including the last Julian day are reckoned in the Julian
+// calendar, dates from the first Gregorian day on in the Gregorian calendar,
+// and the dates in between never happened.
+type Reform struct {
+	name           string
+	lastJulian     civilDate
+	firstGregorian civilDate
+	alwaysJulian   bool
+	neverJulian    bool
+}
+
+var (
+	// ReformGregorian applies the Gregorian calendar to all dates (the
+	// proleptic Gregorian calendar, as used by ISO 8601 and package time).
+	ReformGregorian = Reform{name: "gregorian", neverJulian: true}
+	// ReformJulian applies the Julian calendar to all dates.
+	ReformJulian = Reform{name: "julian", alwaysJulian: true}
+	// Reform1582 is the original reform adopted by Catholic countries:
+	// Thursday, October 4, 1582 was followed by Friday, October 15.
+	Reform1582 = Reform{
+		name:           "1582",
+		lastJulian:     civilDate{1582, time.October, 4},
+		firstGregorian: civilDate{1582, time.October, 15},
+	}
+	// Reform1752 is the reform adopted by Great Britain and its colonies:
+	// Wednesday, September 2, 1752 was followed by Thursday, September 14.
+	// This is the reform used by the traditional UNIX cal.
+	Reform1752 = Reform{
+		name:           "1752",
+		lastJulian:     civilDate{1752, time.September, 2},
+		firstGregorian: civilDate{1752, time.September, 14},
+	}
+	// Reform1918 is the reform adopted by Russia: Wednesday, January 31, 1918
+	// was followed by Thursday, February 14.
+	Reform1918 = Reform{
+		name:           "1918",
+		lastJulian:     civilDate{1918, time.January, 31},
+		firstGregorian: civilDate{1918, time.February, 14},
+	}
+)
+
+// reformAliases maps the names accepted by ParseReform to reforms.
+var reformAliases = map[string]Reform{
+	"gregorian": ReformGregorian,
+	"never":     ReformGregorian,
+	"iso":       ReformGregorian,
+	"julian":    ReformJulian,
+	"1582":      Reform1582,
+	"catholic":  Reform1582,
+	"1752":      Reform1752,
+	"britain":   Reform1752,
+	"gb":        Reform1752,
+	"1918":      Reform1918,
+	"russia":    Reform1918,
+}
+
+// ParseReform parses the name of a reform: "1582" (or "catholic"), "1752"
+// (or "britain"), "1918" (or "russia"), "gregorian" (or "never") and "julian".
+func ParseReform(s string) (Reform, error) {
+	r, ok := reformAliases[strings.ToLower(s)]
+	if !ok {
+		return Reform{}, errors.Errorf("invalid reform %q, expected 1582, 1752, 1918, gregorian or julian", s)
+	}
+	return r, nil
+}
+
+// String returns the canonical name of the reform.
+func (r Reform) String() string {
+	return r.name
+}
+
+// isJulian reports whether d is reckoned in the Julian calendar.
+func (r Reform) isJulian(d civilDate) bool {
+	switch {
+	case r.alwaysJulian:
+		return true
+	case r.neverJulian:
+		return false
+	default:
+		return !r.lastJulian.before(d)
+	}
+}
+
+// Date returns the year, month and day of t, a date in the proleptic
+// Gregorian calendar of package time, as written under the reform: in the
+// Julian calendar up to the last Julian day.
+func (r Reform) Date(t time.Time) (year int, month time.Month, day int) {
+	year, month, day = t.Date()
+	if r.neverJulian {
+		return year, month, day
+	}
+	if d := jdnToJulian(gregorianToJDN(year, month, day)); r.isJulian(d) {
+		return d.year, d.month, d.day
+	}
+	return year, month, day
+}
+
+// exists reports whether d is a date that took place, i.e. does not fall
+// into the days skipped by the reform.
+func (r Reform) exists(d civilDate) bool {
+	if r.alwaysJulian || r.neverJulian {
+		return true
+	}
+	return !r.lastJulian.before(d) || !d.before(r.firstGregorian)
+}
+
+// daysIn returns the number of the last day of the month. The month of the
+// reform keeps the length it has in the calendar it ends in.
+func (r Reform) daysIn(month time.Month, year int) int {
+	if r.isJulian(civilDate{year, month, 31}) {
+		return julianDaysIn(month, year)
+	}
+	return gregorianDaysIn(month, year)
+}
+
+// julianDayNumber returns the Julian Day Number of d, a count of days that
+// is the same in both calendars.
+func (r Reform) julianDayNumber(d civilDate) int {
+	if r.isJulian(d) {
+		return julianToJDN(d.year, d.month, d.day)
+	}
+	return gregorianToJDN(d.year, d.month, d.day)
+}
+
+// reformDay is a day of a month laid out under a reform.
+type reformDay struct {
+	day     int
+	weekday time.Weekday
+	yearDay int
+	// date is the same day in the proleptic Gregorian calendar of package
+	// time, at midnight UTC.
+	date time.Time
+}
+
+// monthDays returns the days of the month that took place under the reform.
+//
+// As in the traditional cal, the day of the year follows the dates as
+// written, so the days skipped by the reform leave a gap in the numbering.
+func (r Reform) monthDays(month time.Month, year int) []reformDay {
+	yearDay := 0
+	for m := time.January; m < month; m++ {
+		yearDay += r.daysIn(m, year)
+	}
+	n := r.daysIn(month, year)
+	days := make([]reformDay, 0, n)
+	for d := 1; d <= n; d++ {
+		cd := civilDate{year, month, d}
+		if !r.exists(cd) {
+			continue
+		}
+		jdn := r.julianDayNumber(cd)
+		days = append(days, reformDay{
+			day:     d,
+			weekday: jdnWeekday(jdn),
+			yearDay: yearDay + d,
+			date:    jdnToTime(jdn),
+		})
+	}
+	return days
+}
+
+// julianEpochUnixDay is the Julian Day Number of January 1, 1970.
+const julianEpochUnixDay = 2440588
+
+// gregorianToJDN converts a proleptic Gregorian date to a Julian Day Number.
+func gregorianToJDN(year int, month time.Month, day int) int {
+	a := (14 - int(month)) / 12
+	y := year + 4800 - a
+	m := int(month) + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + floorDiv(y, 4) - floorDiv(y, 100) + floorDiv(y, 400) - 32045
+}
+
+// julianToJDN converts a proleptic Julian date to a Julian Day Number.
+func julianToJDN(year int, month time.Month, day int) int {
+	a := (14 - int(month)) / 12
+	y := year + 4800 - a
+	m := int(month) + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + floorDiv(y, 4) - 32083
+}
+
+// jdnToJulian converts a Julian Day Number to a proleptic Julian date.
+func jdnToJulian(jdn int) civilDate {
+	c := jdn + 32082
+	d := floorDiv(4*c+3, 1461)
+	e := c - floorDiv(1461*d, 4)
+	m := (5*e + 2) / 153
+	return civilDate{
+		year:  d - 4800 + m/10,
+		month: time.Month(m + 3 - 12*(m/10)),
+		day:   e - (153*m+2)/5 + 1,
+	}
+}
+
+// jdnWeekday returns the weekday of a Julian Day Number.
+func jdnWeekday(jdn int) time.Weekday {
+	return time.Weekday((jdn%7 + 8) % 7)
+}
+
+// jdnToTime returns midnight UTC of a Julian Day Number.
+func jdnToTime(jdn int) time.Time {
+	return time.Unix(int64(jdn-julianEpochUnixDay)*86400, 0).UTC()
+}
+
+// julianDaysIn returns the length of a month in the Julian calendar.
+func julianDaysIn(month time.Month, year int) int {
+	if month == time.February {
+		if floorMod(year, 4) == 0 {
+			return 29
+		}
+		return 28
+	}
+	return gregorianDaysIn(month, year)
+}
+
+// gregorianDaysIn returns the length of a month in the Gregorian calendar.
+func gregorianDaysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns the remainder of floorDiv, which has the sign of b.
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
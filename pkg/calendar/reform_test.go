@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReformMonthCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		month    time.Month
+		year     int
+		opts     []Option
+		expected string
+	}{
+		{
+			name:  "September 1752 in Britain",
+			month: time.September,
+			year:  1752,
+			opts:  []Option{WithReform(Reform1752)},
+			expected: "   September 1752   \n" +
+				"Su Mo Tu We Th Fr Sa\n" +
+				"       1  2 14 15 16 \n" +
+				"17 18 19 20 21 22 23 \n" +
+				"24 25 26 27 28 29 30 \n\n",
+		},
+		{
+			name:  "September 1752 with days of the year",
+			month: time.September,
+			year:  1752,
+			opts:  []Option{WithReform(Reform1752), WithDayOfYear(true)},
+			expected: "      September 1752       \n" +
+				" Su  Mo  Tu  We  Th  Fr  Sa\n" +
+				"        245 246 258 259 260 \n" +
+				"261 262 263 264 265 266 267 \n" +
+				"268 269 270 271 272 273 274 \n\n",
+		},
+		{
+			name:  "October 1582 in Catholic countries",
+			month: time.October,
+			year:  1582,
+			opts:  []Option{WithReform(Reform1582)},
+			expected: "    October 1582    \n" +
+				"Su Mo Tu We Th Fr Sa\n" +
+				"    1  2  3  4 15 16 \n" +
+				"17 18 19 20 21 22 23 \n" +
+				"24 25 26 27 28 29 30 \n" +
+				"31 \n",
+		},
+		{
+			name:  "February 1918 in Russia",
+			month: time.February,
+			year:  1918,
+			opts:  []Option{WithReform(Reform1918)},
+			expected: "   February 1918    \n" +
+				"Su Mo Tu We Th Fr Sa\n" +
+				"            14 15 16 \n" +
+				"17 18 19 20 21 22 23 \n" +
+				"24 25 26 27 28 \n",
+		},
+		{
+			name:  "Julian leap day before the British reform",
+			month: time.February,
+			year:  1700,
+			opts:  []Option{WithReform(Reform1752)},
+			expected: "   February 1700    \n" +
+				"Su Mo Tu We Th Fr Sa\n" +
+				"             1  2  3 \n" +
+				" 4  5  6  7  8  9 10 \n" +
+				"11 12 13 14 15 16 17 \n" +
+				"18 19 20 21 22 23 24 \n" +
+				"25 26 27 28 29 \n",
+		},
+		{
+			name:  "proleptic Gregorian September 1752",
+			month: time.September,
+			year:  1752,
+			expected: "   September 1752   \n" +
+				"Su Mo Tu We Th Fr Sa\n" +
+				"                1  2 \n" +
+				" 3  4  5  6  7  8  9 \n" +
+				"10 11 12 13 14 15 16 \n" +
+				"17 18 19 20 21 22 23 \n" +
+				"24 25 26 27 28 29 30 \n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := stripAnsiCodes(buildMonthCalendar(tt.month, tt.year, tt.opts...))
+			assert.Equal(t, tt.expected, result, "Calendar output should match expected format")
+		})
+	}
+}
+
+func TestJulianDayNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		reform   Reform
+		date     civilDate
+		expected int
+	}{
+		{name: "Unix epoch", reform: ReformGregorian, date: civilDate{1970, time.January, 1}, expected: 2440588},
+		{name: "last Julian day in Britain", reform: Reform1752, date: civilDate{1752, time.September, 2}, expected: 2361221},
+		{name: "first Gregorian day in Britain", reform: Reform1752, date: civilDate{1752, time.September, 14}, expected: 2361222},
+		{name: "Julian calendar epoch", reform: ReformJulian, date: civilDate{-4712, time.January, 1}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.reform.julianDayNumber(tt.date), "julianDayNumber should return the expected day")
+		})
+	}
+}
+
+func TestReformMonthDays(t *testing.T) {
+	days := Reform1752.monthDays(time.September, 1752)
+	assert.Len(t, days, 19, "September 1752 should have 19 days")
+	assert.Equal(t, 2, days[1].day)
+	assert.Equal(t, time.Wednesday, days[1].weekday)
+	assert.Equal(t, 14, days[2].day)
+	assert.Equal(t, time.Thursday, days[2].weekday)
+	assert.Equal(t, time.Date(1752, time.September, 14, 0, 0, 0, 0, time.UTC), days[2].date)
+	assert.Equal(t, time.Date(1752, time.September, 13, 0, 0, 0, 0, time.UTC), days[1].date, "Julian September 2 is Gregorian September 13")
+}
+
+func TestParseReform(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Reform
+		wantErr  bool
+	}{
+		{input: "1752", expected: Reform1752},
+		{input: "Britain", expected: Reform1752},
+		{input: "1582", expected: Reform1582},
+		{input: "russia", expected: Reform1918},
+		{input: "never", expected: ReformGregorian},
+		{input: "julian", expected: ReformJulian},
+		{input: "1700", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseReform(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result, "ParseReform should return the expected reform")
+		})
+	}
+}
+
+func TestReformDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		reform   Reform
+		date     time.Time
+		expected civilDate
+	}{
+		{name: "after the reform", reform: Reform1752, date: time.Date(1752, time.September, 14, 0, 0, 0, 0, time.UTC), expected: civilDate{1752, time.September, 14}},
+		{name: "last Julian day", reform: Reform1752, date: time.Date(1752, time.September, 13, 0, 0, 0, 0, time.UTC), expected: civilDate{1752, time.September, 2}},
+		{name: "Julian new year", reform: Reform1752, date: time.Date(1700, time.January, 11, 0, 0, 0, 0, time.UTC), expected: civilDate{1700, time.January, 1}},
+		{name: "Julian leap day", reform: ReformJulian, date: time.Date(1900, time.March, 13, 0, 0, 0, 0, time.UTC), expected: civilDate{1900, time.February, 29}},
+		{name: "proleptic Gregorian", reform: ReformGregorian, date: time.Date(1000, time.June, 1, 0, 0, 0, 0, time.UTC), expected: civilDate{1000, time.June, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			year, month, day := tt.reform.Date(tt.date)
+			assert.Equal(t, tt.expected, civilDate{year, month, day})
+		})
+	}
+}
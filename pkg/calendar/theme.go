@@ -0,0 +1,281 @@
+package calendar
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Style is how an element of a calendar is highlighted. It is written as
+// words separated by spaces: attributes such as "bold" or "underline", a
+// foreground color and, after "on", a background color, e.g. "bold yellow on
+// blue". Colors are one of the 16 terminal colors ("red", "bright-red"), a
+// number of the 256-color palette ("208") or a truecolor "#rrggbb" value.
+type Style struct {
+	spec  string
+	attrs []color.Attribute
+}
+
+// styleAttributes maps the attribute words of a Style to SGR codes.
+var styleAttributes = map[string]color.Attribute{
+	"bold":          color.Bold,
+	"faint":         color.Faint,
+	"dim":           color.Faint,
+	"italic":        color.Italic,
+	"underline":     color.Underline,
+	"blink":         color.BlinkSlow,
+	"reverse":       color.ReverseVideo,
+	"strikethrough": color.CrossedOut,
+}
+
+// styleColors lists the names of the 16 terminal colors without the "bright-"
+// prefix, in SGR order.
+var styleColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ParseStyle parses a style such as "bold black on #ffd700". An empty string
+// is the style that leaves text unchanged.
+func ParseStyle(spec string) (Style, error) {
+	words := strings.Fields(spec)
+	s := Style{spec: strings.Join(words, " ")}
+	var background, fg, bg bool
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if attr, ok := styleAttributes[word]; ok {
+			s.attrs = append(s.attrs, attr)
+			continue
+		}
+		if word == "on" {
+			if background || i == len(words)-1 {
+				return Style{}, errors.Errorf("invalid style %q: \"on\" must be followed by a single background color", spec)
+			}
+			background = true
+			continue
+		}
+		set, layer := &fg, "foreground"
+		if background {
+			set, layer = &bg, "background"
+		}
+		if *set {
+			return Style{}, errors.Errorf("invalid style %q: more than one %s color", spec, layer)
+		}
+		attrs, err := parseColor(word, background)
+		if err != nil {
+			return Style{}, errors.Wrapf(err, "invalid style %q", spec)
+		}
+		s.attrs = append(s.attrs, attrs...)
+		*set = true
+	}
+	return s, nil
+}
+
+// MustParseStyle is like ParseStyle but panics if spec is invalid. It is
+// meant for initializing built-in themes.
+func MustParseStyle(spec string) Style {
+	s, err := ParseStyle(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// parseColor returns the SGR codes selecting a color as the foreground or the
+// background.
+func parseColor(word string, background bool) ([]color.Attribute, error) {
+	base, extended := color.Attribute(30), color.Attribute(38)
+	if background {
+		base, extended = 40, 48
+	}
+	name := strings.TrimPrefix(word, "bright-")
+	for i, c := range styleColors {
+		if c != name {
+			continue
+		}
+		if name != word {
+			return []color.Attribute{base + 60 + color.Attribute(i)}, nil
+		}
+		return []color.Attribute{base + color.Attribute(i)}, nil
+	}
+	if n, err := strconv.Atoi(word); err == nil {
+		if n < 0 || n > 255 {
+			return nil, errors.Errorf("color %d not in range 0..255", n)
+		}
+		return []color.Attribute{extended, 5, color.Attribute(n)}, nil
+	}
+	if hexColor.MatchString(word) {
+		rgb, _ := strconv.ParseUint(word[1:], 16, 32)
+		return []color.Attribute{extended, 2, color.Attribute(rgb >> 16), color.Attribute(rgb >> 8 & 0xff), color.Attribute(rgb & 0xff)}, nil
+	}
+	return nil, errors.Errorf("unknown color or attribute %q", word)
+}
+
+// String returns the style as accepted by ParseStyle.
+func (s Style) String() string {
+	return s.spec
+}
+
+// IsZero reports whether s leaves text unchanged.
+func (s Style) IsZero() bool {
+	return len(s.attrs) == 0
+}
+
+// UnmarshalYAML parses a style written as a YAML string.
+func (s *Style) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return errors.Errorf("line %d: a style must be a string such as \"bold red\"", node.Line)
+	}
+	style, err := ParseStyle(node.Value)
+	if err != nil {
+		return errors.Errorf("line %d: %s", node.Line, err)
+	}
+	*s = style
+	return nil
+}
+
+// MarshalYAML writes the style as a YAML string.
+func (s Style) MarshalYAML() (any, error) {
+	return s.spec, nil
+}
+
+// Theme holds the styles of the elements of a calendar. Days outside the
+// month take precedence over today, today over holidays and holidays over
+// weekends; events are marked in addition to any of those.
+type Theme struct {
+	// Today is the style of the current day, see WithToday.
+	Today Style `yaml:"today,omitempty"`
+	// Weekend is the style of weekend days.
+	Weekend Style `yaml:"weekend,omitempty"`
+	// Holiday is the style of public holidays.
+	Holiday Style `yaml:"holiday,omitempty"`
+	// Event is the style of days with events.
+	Event Style `yaml:"event,omitempty"`
+	// Title is the style of month titles and the year banner.
+	Title Style `yaml:"title,omitempty"`
+	// Weekday is the style of the weekday names.
+	Weekday Style `yaml:"weekday,omitempty"`
+	// Outside is the style of the days of adjacent months, see
+	// WithOutsideDays.
+	Outside Style `yaml:"outside,omitempty"`
+}
+
+// elements returns the styles of t by the names used in theme files.
+func (t *Theme) elements() map[string]*Style {
+	return map[string]*Style{
+		"today":   &t.Today,
+		"weekend": &t.Weekend,
+		"holiday": &t.Holiday,
+		"event":   &t.Event,
+		"title":   &t.Title,
+		"weekday": &t.Weekday,
+		"outside": &t.Outside,
+	}
+}
+
+// UnmarshalYAML reads a theme from a YAML mapping of element names to styles.
+// The optional "base" key names a built-in theme whose styles are used for
+// the elements that are not listed; otherwise they keep their current style.
+func (t *Theme) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.Errorf("line %d: a theme must be a mapping of elements to styles", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key, value := node.Content[i], node.Content[i+1]; key.Value == "base" {
+			base, err := LookupTheme(value.Value)
+			if err != nil {
+				return errors.Errorf("line %d: %s", value.Line, err)
+			}
+			*t = *base
+		}
+	}
+
+	elements := t.elements()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "base" {
+			continue
+		}
+		style, ok := elements[key.Value]
+		if !ok {
+			return errors.Errorf("line %d: unknown theme element %q, expected base or one of %s", key.Line, key.Value, strings.Join(themeElements(), ", "))
+		}
+		if err := value.Decode(style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// themeElements returns the element names of a theme in alphabetical order.
+func themeElements() []string {
+	var names []string
+	for name := range new(Theme).elements() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseTheme reads a theme file in YAML, for example
+//
+//	base: high-contrast
+//	today: "bold black on #ffd700"
+//	weekend: 208
+//
+// Elements that are not listed keep the style of the base theme, or of
+// DefaultTheme without a base. Styles starting with or containing "#" must be
+// quoted, as YAML treats the rest of the line as a comment otherwise.
+func ParseTheme(data []byte) (*Theme, error) {
+	t := *DefaultTheme
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, errors.Wrap(err, "invalid theme")
+	}
+	return &t, nil
+}
+
+// DefaultTheme highlights today in black on white, weekends in red, holidays
+// in magenta and underlines days with events.
+var DefaultTheme = &Theme{
+	Today:   MustParseStyle("black on white"),
+	Weekend: MustParseStyle("red"),
+	Holiday: MustParseStyle("magenta"),
+	Event:   MustParseStyle("underline"),
+}
+
+// themes holds the built-in themes by name.
+var themes = map[string]*Theme{
+	"default": DefaultTheme,
+	"high-contrast": {
+		Today:   MustParseStyle("bold black on bright-yellow"),
+		Weekend: MustParseStyle("bold bright-red"),
+		Holiday: MustParseStyle("bold bright-magenta"),
+		Event:   MustParseStyle("underline"),
+		Title:   MustParseStyle("bold bright-white"),
+		Weekday: MustParseStyle("bold bright-cyan"),
+		Outside: MustParseStyle("bright-black"),
+	},
+	"plain": {},
+}
+
+// Themes returns the names of the built-in themes in alphabetical order.
+func Themes() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTheme returns the built-in theme with the given name.
+func LookupTheme(name string) (*Theme, error) {
+	if t, ok := themes[strings.ToLower(name)]; ok {
+		return t, nil
+	}
+	return nil, errors.Errorf("unknown theme %q, expected one of %s", name, strings.Join(Themes(), ", "))
+}
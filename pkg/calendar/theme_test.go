@@ -0,0 +1,126 @@
+package calendar
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected []color.Attribute
+		wantErr  bool
+	}{
+		{name: "empty", spec: "", expected: nil},
+		{name: "16 colors", spec: "black on white", expected: []color.Attribute{color.FgBlack, color.BgWhite}},
+		{name: "bright colors", spec: "bright-red on bright-blue", expected: []color.Attribute{color.FgHiRed, color.BgHiBlue}},
+		{name: "attributes", spec: "Bold  underline green", expected: []color.Attribute{color.Bold, color.Underline, color.FgGreen}},
+		{name: "256 colors", spec: "208 on 17", expected: []color.Attribute{38, 5, 208, 48, 5, 17}},
+		{name: "truecolor", spec: "#FFD700", expected: []color.Attribute{38, 2, 255, 215, 0}},
+		{name: "background only", spec: "on cyan", expected: []color.Attribute{color.BgCyan}},
+		{name: "unknown color", spec: "purple", wantErr: true},
+		{name: "256 colors out of range", spec: "256", wantErr: true},
+		{name: "short hex color", spec: "#fff", wantErr: true},
+		{name: "two foreground colors", spec: "red green", wantErr: true},
+		{name: "missing background", spec: "red on", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseStyle(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, s.attrs)
+			assert.Equal(t, len(tt.expected) == 0, s.IsZero())
+		})
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme([]byte("weekend: red\ntitle: \"bold #336699\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTheme.Today, theme.Today, "unlisted elements keep the default style")
+	assert.Equal(t, "red", theme.Weekend.String())
+	assert.Equal(t, "bold #336699", theme.Title.String())
+
+	theme, err = ParseTheme([]byte("base: high-contrast\ntoday: reverse\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "reverse", theme.Today.String())
+	assert.Equal(t, themes["high-contrast"].Weekend, theme.Weekend, "unlisted elements keep the style of the base theme")
+
+	theme, err = ParseTheme(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTheme, theme, "an empty file is the default theme")
+}
+
+func TestParseThemeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{name: "unknown element", input: "today: bold\nsunday: red\n", err: `line 2: unknown theme element "sunday"`},
+		{name: "invalid style", input: "today: bold\n\nweekend: purple\n", err: `line 3: invalid style "purple"`},
+		{name: "style is not a string", input: "title:\n  - bold\n", err: "line 2: a style must be a string"},
+		{name: "unknown base", input: "base: neon\n", err: `line 1: unknown theme "neon"`},
+		{name: "not a mapping", input: "- today\n", err: "line 1: a theme must be a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tt.input))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestLookupTheme(t *testing.T) {
+	for _, name := range Themes() {
+		theme, err := LookupTheme(name)
+		assert.NoError(t, err)
+		assert.NotNil(t, theme)
+	}
+	_, err := LookupTheme("neon")
+	assert.ErrorContains(t, err, "default, high-contrast, plain")
+}
+
+func TestRenderMonthTheme(t *testing.T) {
+	theme := &Theme{
+		Today:   MustParseStyle("reverse"),
+		Weekend: MustParseStyle("red"),
+		Title:   MustParseStyle("bold"),
+		Weekday: MustParseStyle("faint"),
+		Outside: MustParseStyle("bright-black"),
+	}
+	today := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	err := RenderMonth(&buf, time.October, 2026, WithTheme(theme), WithToday(today), WithColor(ColorAlways), WithOutsideDays(true))
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "\x1b[1mOctober 2026\x1b[22m", "the title is bold")
+	assert.Contains(t, out, "\x1b[31mSu\x1b[0m \x1b[2mMo\x1b[22m", "weekday names are faint, except for the weekend")
+	assert.Contains(t, out, "\x1b[7m17\x1b[27m", "today takes precedence over the weekend")
+	assert.Contains(t, out, "\x1b[31m 4\x1b[0m", "weekends are red")
+	assert.Contains(t, out, "\x1b[90m27\x1b[0m", "days of September are grey")
+	assert.Equal(t, "    October 2026    \n"+
+		"Su Mo Tu We Th Fr Sa\n"+
+		"27 28 29 30  1  2  3 \n"+
+		" 4  5  6  7  8  9 10 \n"+
+		"11 12 13 14 15 16 17 \n"+
+		"18 19 20 21 22 23 24 \n"+
+		"25 26 27 28 29 30 31 \n\n", stripAnsiCodes(out))
+
+	buf.Reset()
+	err = RenderMonth(&buf, time.October, 2026, WithTheme(theme), WithToday(today), WithColor(ColorNever))
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), "\x1b[", "themes only apply when color is enabled")
+}
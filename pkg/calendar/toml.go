@@ -0,0 +1,155 @@
+package calendar
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// parseTOML converts a TOML document to a YAML node, so that TOML files are
+// decoded like YAML ones and errors give the same line numbers. Dates and
+// times become strings. It returns nil for an empty document.
+func parseTOML(data []byte) (*yaml.Node, error) {
+	// Decoding first reports the syntax errors, duplicate keys and
+	// redefined tables that the parser below does not check.
+	var decoded map[string]any
+	if err := toml.Unmarshal(data, &decoded); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, _ := decodeErr.Position()
+			return nil, errors.Errorf("line %d: %s", line, strings.TrimPrefix(decodeErr.Error(), "toml: "))
+		}
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, nil
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1}
+	table := root
+	var p unstable.Parser
+	p.Reset(data)
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = root
+			keys := tomlKeys(&p, expr.Key())
+			for i, key := range keys {
+				if i == len(keys)-1 && expr.Kind == unstable.ArrayTable {
+					table = appendTable(table, key)
+				} else {
+					table = subTable(table, key)
+				}
+			}
+
+		case unstable.KeyValue:
+			appendKeyValue(&p, table, expr, 0)
+		}
+	}
+	if err := p.Error(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// appendKeyValue adds the key/value pair kv to the table m. Values without a
+// position of their own are on the line of the key, or on the given line if
+// it is not 0.
+func appendKeyValue(p *unstable.Parser, m *yaml.Node, kv *unstable.Node, line int) {
+	keys := tomlKeys(p, kv.Key())
+	for _, key := range keys[:len(keys)-1] {
+		m = subTable(m, key)
+	}
+	key := keys[len(keys)-1]
+	if line == 0 {
+		line = key.Line
+	}
+	m.Content = append(m.Content, key, tomlValue(p, kv.Value(), line))
+}
+
+// tomlKeys returns the parts of a dotted key as YAML keys.
+func tomlKeys(p *unstable.Parser, it unstable.Iterator) []*yaml.Node {
+	var keys []*yaml.Node
+	for it.Next() {
+		n := it.Node()
+		line := p.Shape(n.Raw).Start.Line
+		keys = append(keys, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(n.Data), Line: line})
+	}
+	return keys
+}
+
+// subTable returns the table of key in m, creating it if needed. The key of
+// an array of tables selects its last table.
+func subTable(m, key *yaml.Node) *yaml.Node {
+	value := mappingValue(m, key.Value)
+	switch {
+	case value == nil:
+		value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: key.Line}
+		m.Content = append(m.Content, key, value)
+	case value.Kind == yaml.SequenceNode:
+		value = value.Content[len(value.Content)-1]
+	}
+	return value
+}
+
+// appendTable appends a new table to the array of tables of key in m.
+func appendTable(m, key *yaml.Node) *yaml.Node {
+	array := mappingValue(m, key.Value)
+	if array == nil {
+		array = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: key.Line}
+		m.Content = append(m.Content, key, array)
+	}
+	table := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: key.Line}
+	array.Content = append(array.Content, table)
+	return table
+}
+
+// tomlValue converts a TOML value to a YAML node. Values without a position
+// of their own, such as arrays, are on the given line.
+func tomlValue(p *unstable.Parser, n *unstable.Node, line int) *yaml.Node {
+	if n.Raw.Length > 0 {
+		line = p.Shape(n.Raw).Start.Line
+	}
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(n.Data), Line: line}
+	switch n.Kind {
+	case unstable.Array:
+		node.Kind, node.Tag, node.Value = yaml.SequenceNode, "!!seq", ""
+		for it := n.Children(); it.Next(); {
+			node.Content = append(node.Content, tomlValue(p, it.Node(), line))
+		}
+	case unstable.InlineTable:
+		node.Kind, node.Tag, node.Value = yaml.MappingNode, "!!map", ""
+		for it := n.Children(); it.Next(); {
+			keys := tomlKeys(p, it.Node().Key())
+			parent := node
+			for _, key := range keys[:len(keys)-1] {
+				parent = subTable(parent, key)
+			}
+			parent.Content = append(parent.Content, keys[len(keys)-1], tomlValue(p, it.Node().Value(), line))
+		}
+	case unstable.Integer:
+		// The decoded document is valid, so the integer parses.
+		i, _ := strconv.ParseInt(strings.ReplaceAll(node.Value, "_", ""), 0, 64)
+		node.Tag, node.Value = "!!int", strconv.FormatInt(i, 10)
+	case unstable.Float:
+		node.Tag = "!!float"
+	case unstable.Bool:
+		node.Tag = "!!bool"
+	}
+	return node
+}
+
+// mappingValue returns the value of key in the mapping node m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
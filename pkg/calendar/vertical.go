@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Layout selects how the days of a month are arranged.
+type Layout int
+
+const (
+	// LayoutHorizontal shows a week per row, like cal.
+	LayoutHorizontal Layout = iota
+	// LayoutVertical shows a weekday per row and a week per column, like
+	// ncal.
+	LayoutVertical
+)
+
+// String returns the name accepted by ParseLayout.
+func (l Layout) String() string {
+	switch l {
+	case LayoutHorizontal:
+		return "horizontal"
+	case LayoutVertical:
+		return "vertical"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLayout parses "horizontal" or "vertical".
+func ParseLayout(s string) (Layout, error) {
+	for l := LayoutHorizontal; l <= LayoutVertical; l++ {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return LayoutHorizontal, errors.Errorf("invalid layout %q, expected horizontal or vertical", s)
+}
+
+const (
+	// verticalWeeks is the number of week columns reserved for every month
+	// when several months are laid out side by side.
+	verticalWeeks = 6
+	// verticalLabelWidth is the width of the weekday labels on the left.
+	verticalLabelWidth = 2
+	// verticalGap is the number of spaces between months laid out side by
+	// side.
+	verticalGap = 1
+)
+
+// verticalBlockWidth returns the width of a month with the given number of
+// weeks in the vertical layout, excluding the weekday labels.
+func (o *options) verticalBlockWidth(weeks int) int {
+	return weeks * (o.cellWidth() + 1)
+}
+
+// buildVerticalMonth generates the calendar of m in the vertical layout.
+func buildVerticalMonth(m *Month, o *options) string {
+	var b bytes.Buffer
+	writeVerticalMonths(&b, []*Month{m}, len(m.Weeks), o.locale.monthTitle, o)
+	return b.String()
+}
+
+// writeVerticalMonths writes months side by side in the vertical layout to
+// b, reserving room for the given number of weeks in every month and titling
+// each month with title. The weekday labels are written once, on the left.
+func writeVerticalMonths(b *bytes.Buffer, months []*Month, weeks int, title func(*Month) string, o *options) {
+	blockWidth := o.verticalBlockWidth(weeks)
+	gap := strings.Repeat(" ", verticalGap)
+	blankDay := strings.Repeat(" ", o.cellWidth())
+	var lines []string
+
+	var line strings.Builder
+	line.WriteString(strings.Repeat(" ", verticalLabelWidth))
+	for i, m := range months {
+		if i > 0 {
+			line.WriteString(gap)
+		}
+		line.WriteString(NCenter(blockWidth, o.paint(o.theme.Title, title(m))).String())
+	}
+	lines = append(lines, line.String())
+
+	for column := range 7 {
+		weekday := (o.firstWeekday + time.Weekday(column)) % 7
+		line.Reset()
+		line.WriteString(PadRight(o.weekdayLabel(weekday), verticalLabelWidth))
+		for i, m := range months {
+			// The space before a day holds the event marker of the day
+			// before it in the row.
+			var block strings.Builder
+			var prev *Day
+			for _, week := range m.Weeks {
+				block.WriteString(o.dayGap(prev))
+				if day := week.Days[column]; day != nil {
+					block.WriteString(formatDay(day, o))
+				} else {
+					block.WriteString(blankDay)
+				}
+				prev = week.Days[column]
+			}
+			if marker := o.dayGap(prev); marker != " " {
+				block.WriteString(marker)
+			}
+			// A marker after the last week may take up a column of the gap.
+			width := blockWidth
+			if i < len(months)-1 {
+				width += verticalGap
+			}
+			line.WriteString(PadRight(block.String(), width))
+		}
+		lines = append(lines, line.String())
+	}
+
+	if o.weekNumbers != NoWeekNumbers {
+		line.Reset()
+		line.WriteString(strings.Repeat(" ", verticalLabelWidth))
+		for i, m := range months {
+			if i > 0 {
+				line.WriteString(gap)
+			}
+			var block strings.Builder
+			for _, week := range m.Weeks {
+				fmt.Fprintf(&block, " %*d", o.cellWidth(), week.Number)
+			}
+			line.WriteString(PadRight(block.String(), blockWidth))
+		}
+		lines = append(lines, line.String())
+	}
+
+	for _, l := range lines {
+		b.WriteString(strings.TrimRight(l, " "))
+		b.WriteRune('\n')
+	}
+}
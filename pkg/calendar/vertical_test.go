@@ -0,0 +1,119 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMonthVertical(t *testing.T) {
+	tests := []struct {
+		name     string
+		month    time.Month
+		year     int
+		opts     []Option
+		expected string
+	}{
+		{
+			name:  "October 2026 starting on Monday",
+			month: time.October,
+			year:  2026,
+			opts:  []Option{WithFirstWeekday(time.Monday)},
+			expected: "   October 2026\n" +
+				"Mo     5 12 19 26\n" +
+				"Tu     6 13 20 27\n" +
+				"We     7 14 21 28\n" +
+				"Th  1  8 15 22 29\n" +
+				"Fr  2  9 16 23 30\n" +
+				"Sa  3 10 17 24 31\n" +
+				"Su  4 11 18 25\n",
+		},
+		{
+			name:  "September 1752 with week numbers",
+			month: time.September,
+			year:  1752,
+			opts:  []Option{WithReform(Reform1752), WithWeekNumbers(USWeekNumbers)},
+			expected: "  September 1752\n" +
+				"Su    17 24\n" +
+				"Mo    18 25\n" +
+				"Tu  1 19 26\n" +
+				"We  2 20 27\n" +
+				"Th 14 21 28\n" +
+				"Fr 15 22 29\n" +
+				"Sa 16 23 30\n" +
+				"   38 39 40\n",
+		},
+		{
+			name:  "days of the year",
+			month: time.February,
+			year:  2026,
+			opts:  []Option{WithDayOfYear(true)},
+			expected: "   February 2026\n" +
+				"Su  32  39  46  53\n" +
+				"Mo  33  40  47  54\n" +
+				"Tu  34  41  48  55\n" +
+				"We  35  42  49  56\n" +
+				"Th  36  43  50  57\n" +
+				"Fr  37  44  51  58\n" +
+				"Sa  38  45  52  59\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderMonth(&buf, tt.month, tt.year, append(tt.opts, WithLayout(LayoutVertical))...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, stripAnsiCodes(buf.String()))
+		})
+	}
+}
+
+func TestRenderMonthsVertical(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderMonths(&buf, time.January, 2026, 5, WithLayout(LayoutVertical), WithMonthsPerRow(4), WithFirstWeekday(time.Monday))
+	assert.NoError(t, err)
+	assert.Equal(t, "     January 2026      February 2026        March 2026         April 2026\n"+
+		"Mo     5 12 19 26         2  9 16 23         2  9 16 23 30      6 13 20 27\n"+
+		"Tu     6 13 20 27         3 10 17 24         3 10 17 24 31      7 14 21 28\n"+
+		"We     7 14 21 28         4 11 18 25         4 11 18 25      1  8 15 22 29\n"+
+		"Th  1  8 15 22 29         5 12 19 26         5 12 19 26      2  9 16 23 30\n"+
+		"Fr  2  9 16 23 30         6 13 20 27         6 13 20 27      3 10 17 24\n"+
+		"Sa  3 10 17 24 31         7 14 21 28         7 14 21 28      4 11 18 25\n"+
+		"Su  4 11 18 25         1  8 15 22         1  8 15 22 29      5 12 19 26\n"+
+		"\n"+
+		"       May 2026\n"+
+		"Mo     4 11 18 25\n"+
+		"Tu     5 12 19 26\n"+
+		"We     6 13 20 27\n"+
+		"Th     7 14 21 28\n"+
+		"Fr  1  8 15 22 29\n"+
+		"Sa  2  9 16 23 30\n"+
+		"Su  3 10 17 24 31\n"+
+		"\n", stripAnsiCodes(buf.String()))
+}
+
+func TestParseLayout(t *testing.T) {
+	for _, l := range []Layout{LayoutHorizontal, LayoutVertical} {
+		parsed, err := ParseLayout(l.String())
+		assert.NoError(t, err)
+		assert.Equal(t, l, parsed, "ParseLayout should round-trip String")
+	}
+
+	_, err := ParseLayout("diagonal")
+	assert.Error(t, err)
+}
+
+func TestRenderMonthVerticalWeekend(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderMonth(&buf, time.October, 2026, WithLayout(LayoutVertical), WithWeekend(time.Friday, time.Saturday),
+		WithColor(ColorAlways), WithTheme(&Theme{Weekend: MustParseStyle("red")}))
+	assert.NoError(t, err)
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "Th  1  8 15 22 29", lines[5])
+	assert.Equal(t, "\x1b[31mFr\x1b[0m \x1b[31m 2\x1b[0m \x1b[31m 9\x1b[0m \x1b[31m16\x1b[0m \x1b[31m23\x1b[0m \x1b[31m30\x1b[0m", lines[6])
+	assert.Equal(t, "Su     4 11 18 25", lines[1], "Sunday is a working day")
+}
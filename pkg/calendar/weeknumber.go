@@ -0,0 +1,74 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WeekNumbering selects the week numbers printed in front of each week.
+type WeekNumbering int
+
+const (
+	// NoWeekNumbers disables the week number column.
+	NoWeekNumbers WeekNumbering = iota
+	// ISOWeekNumbers numbers weeks as in ISO 8601: weeks start on Monday and
+	// week 1 is the week containing the first Thursday of the year.
+	ISOWeekNumbers
+	// USWeekNumbers numbers weeks as is common in the United States: weeks
+	// start on Sunday and week 1 is the week containing January 1.
+	USWeekNumbers
+)
+
+// String returns the name accepted by ParseWeekNumbering.
+func (n WeekNumbering) String() string {
+	switch n {
+	case NoWeekNumbers:
+		return "none"
+	case ISOWeekNumbers:
+		return "iso"
+	case USWeekNumbers:
+		return "us"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseWeekNumbering parses "none", "iso" or "us".
+func ParseWeekNumbering(s string) (WeekNumbering, error) {
+	for n := NoWeekNumbers; n <= USWeekNumbers; n++ {
+		if strings.EqualFold(s, n.String()) {
+			return n, nil
+		}
+	}
+	return NoWeekNumbers, errors.Errorf("invalid week numbering %q, expected none, iso or us", s)
+}
+
+// USWeek returns the week of the year of t, counting the week that contains
+// January 1 as week 1 and starting each week on Sunday.
+func USWeek(t time.Time) int {
+	jan1 := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	return (t.YearDay()-1+int(jan1.Weekday()))/7 + 1
+}
+
+// rowWeekNumber returns the week number printed for a calendar row that
+// starts on rowStart, where day is the first day of the row that belongs to
+// the month being displayed.
+//
+// A row does not necessarily line up with the weeks of the numbering, e.g.
+// ISO weeks in a calendar starting on Sunday. The ISO number is therefore
+// taken from the middle of the row, which belongs to the ISO week that
+// covers most of it, and the US number from the first day of the month in
+// the row so that it never refers to the neighbouring year.
+func rowWeekNumber(numbering WeekNumbering, rowStart, day time.Time) int {
+	switch numbering {
+	case ISOWeekNumbers:
+		_, week := rowStart.AddDate(0, 0, 3).ISOWeek()
+		return week
+	case USWeekNumbers:
+		return USWeek(day)
+	default:
+		return 0
+	}
+}
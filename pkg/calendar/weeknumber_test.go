@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUSWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected int
+	}{
+		{name: "January 1 is always week 1", date: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), expected: 1},
+		{name: "first Sunday starts week 2", date: time.Date(2022, time.January, 2, 0, 0, 0, 0, time.UTC), expected: 2},
+		{name: "year starting on Sunday", date: time.Date(2023, time.January, 7, 0, 0, 0, 0, time.UTC), expected: 1},
+		{name: "end of leap year", date: time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC), expected: 53},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, USWeek(tt.date), "USWeek should return the expected week")
+		})
+	}
+}
+
+func TestRowWeekNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		numbering WeekNumbering
+		rowStart  time.Time
+		day       time.Time
+		expected  int
+	}{
+		{
+			name:      "ISO row starting on Monday",
+			numbering: ISOWeekNumbers,
+			rowStart:  time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC),
+			day:       time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expected:  53,
+		},
+		{
+			name:      "ISO row starting on Sunday uses the following Monday's week",
+			numbering: ISOWeekNumbers,
+			rowStart:  time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC),
+			day:       time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC),
+			expected:  1,
+		},
+		{
+			name:      "US row crossing the year boundary",
+			numbering: USWeekNumbers,
+			rowStart:  time.Date(2024, time.December, 29, 0, 0, 0, 0, time.UTC),
+			day:       time.Date(2024, time.December, 29, 0, 0, 0, 0, time.UTC),
+			expected:  53,
+		},
+		{
+			name:      "no week numbers",
+			numbering: NoWeekNumbers,
+			rowStart:  time.Date(2024, time.December, 29, 0, 0, 0, 0, time.UTC),
+			day:       time.Date(2024, time.December, 29, 0, 0, 0, 0, time.UTC),
+			expected:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rowWeekNumber(tt.numbering, tt.rowStart, tt.day)
+			assert.Equal(t, tt.expected, result, "rowWeekNumber should return the expected week")
+		})
+	}
+}
+
+func TestParseWeekNumbering(t *testing.T) {
+	for _, n := range []WeekNumbering{NoWeekNumbers, ISOWeekNumbers, USWeekNumbers} {
+		parsed, err := ParseWeekNumbering(n.String())
+		assert.NoError(t, err)
+		assert.Equal(t, n, parsed, "ParseWeekNumbering should round-trip String")
+	}
+
+	_, err := ParseWeekNumbering("julian")
+	assert.Error(t, err)
+}
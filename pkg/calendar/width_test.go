@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{name: "ASCII", input: "October 2026", expected: 12},
+		{name: "Latin with precomposed accents", input: "février", expected: 7},
+		{name: "combining acute accent", input: "fe\u0301vrier", expected: 7},
+		{name: "Cyrillic", input: "Октябрь", expected: 7},
+		{name: "Japanese", input: "2026年10月", expected: 10},
+		{name: "Hangul", input: "2026년 10월", expected: 11},
+		{name: "fullwidth digits", input: "１２", expected: 4},
+		{name: "emoji", input: "🎉 Party", expected: 8},
+		{name: "emoji with variation selector", input: "⚡️", expected: 2},
+		{name: "ANSI color codes", input: "\x1b[47;30m17\x1b[0m", expected: 2},
+		{name: "empty string", input: "", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DisplayWidth(tt.input), "DisplayWidth should count terminal columns")
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "日  ", PadRight("日", 4))
+	assert.Equal(t, "  日", PadLeft("日", 4))
+	assert.Equal(t, "\x1b[1m17\x1b[0m ", PadRight("\x1b[1m17\x1b[0m", 3), "escape sequences should not count")
+	assert.Equal(t, "toolong", PadRight("toolong", 3), "wider strings should be returned unchanged")
+	assert.Equal(t, "toolong", PadLeft("toolong", 3), "wider strings should be returned unchanged")
+}
@@ -0,0 +1,63 @@
+package calendar
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RenderYear writes the calendar for an entire year to w. The year is shown
+// once, in a banner centered over the months, which are titled by name only.
+func RenderYear(w io.Writer, year int, opts ...Option) error {
+	o := newWriterOptions(w, opts)
+	perRow := min(o.monthsPerRow, 12)
+
+	var b bytes.Buffer
+	var months []*Month
+	b.WriteString(strings.TrimRight(NCenter(o.rowWidth(perRow), o.paint(o.theme.Title, o.locale.YearTitle(year))).String(), " "))
+	b.WriteString("\n\n")
+
+	for first := time.January; first <= time.December; first += time.Month(perRow) {
+		var row []*Month
+		for month := first; month <= time.December && month < first+time.Month(perRow); month++ {
+			row = append(row, newMonth(month, year, o))
+		}
+		months = append(months, row...)
+
+		if o.layout == LayoutVertical {
+			writeVerticalMonths(&b, row, verticalWeeks, o.yearMonthTitle, o)
+			b.WriteRune('\n')
+			continue
+		}
+		monthStrings := make([][]string, len(row))
+		for i, m := range row {
+			monthStrings[i] = splitLines(buildYearMonth(m, o))
+		}
+		if err := writeMonthRow(&b, monthStrings, o.monthWidth()); err != nil {
+			return err
+		}
+	}
+	writeEventLegend(&b, months, o)
+
+	_, err := w.Write(b.Bytes())
+	return errors.Wrap(err, "error writing year calendar")
+}
+
+// yearMonthTitle returns the title of m inside the calendar of a year, which
+// leaves out the year shown in the banner.
+func (o *options) yearMonthTitle(m *Month) string {
+	return o.locale.MonthName(m.Month)
+}
+
+// buildYearMonth generates the calendar of a month inside the calendar of a
+// year.
+func buildYearMonth(m *Month, o *options) string {
+	b := bytes.NewBufferString(strings.Repeat(" ", o.weekNumberWidth()))
+	b.Write(NCenter(o.daysWidth(), o.paint(o.theme.Title, o.yearMonthTitle(m))).Bytes())
+	b.WriteRune('\n')
+	writeMonthGrid(b, m, o)
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderYearBanner(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		banner string
+		titles string
+	}{
+		{
+			name:   "default",
+			banner: "                                2026",
+			titles: "      January                 February                 March            ",
+		},
+		{
+			name:   "week numbers",
+			opts:   []Option{WithWeekNumbers(ISOWeekNumbers)},
+			banner: "                                    2026",
+			titles: "         January                    February                    March            ",
+		},
+		{
+			name:   "Japanese",
+			opts:   []Option{WithLocale(locales["ja"])},
+			banner: "                               2026年",
+			titles: "        1月                     2月                     3月             ",
+		},
+		{
+			name:   "vertical",
+			opts:   []Option{WithLayout(LayoutVertical), WithFirstWeekday(time.Monday)},
+			banner: "                           2026",
+			titles: "       January            February            March",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderYear(&buf, 2026, tt.opts...)
+			assert.NoError(t, err)
+			lines := strings.Split(stripAnsiCodes(buf.String()), "\n")
+			assert.Equal(t, tt.banner, lines[0])
+			assert.Equal(t, "", lines[1])
+			assert.Equal(t, tt.titles, lines[2])
+			assert.NotContains(t, strings.Join(lines[1:], "\n"), "2026", "the year should only appear in the banner")
+		})
+	}
+}
+
+func TestRenderYearWeekend(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderYear(&buf, 2026, WithWeekend(time.Sunday), WithColor(ColorAlways), WithTheme(&Theme{Weekend: MustParseStyle("red")}))
+	assert.NoError(t, err)
+	assert.Equal(t, 52+12, strings.Count(buf.String(), "\x1b[31m"), "the 52 Sundays of 2026 and the Sunday column of 12 weekday headers")
+	assert.NotContains(t, buf.String(), "\x1b[31mSa", "Saturday is a working day")
+}
@@ -0,0 +1,538 @@
+// Package ical reads the events of iCalendar files as defined by RFC 5545,
+// such as the exports of calendar applications.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mojotx/cal/pkg/rrule"
+	"github.com/pkg/errors"
+)
+
+// Event is an event (VEVENT) of an iCalendar file.
+type Event struct {
+	// UID identifies the event.
+	UID string
+	// Summary is the title of the event.
+	Summary string
+	// Location and Description are the place and the notes of the event.
+	Location    string
+	Description string
+	// Start is the start of the event. All-day events start at midnight UTC
+	// of their first day.
+	Start time.Time
+	// End is the end of the event, which is not part of it. All-day events
+	// end at midnight UTC of the day after their last day.
+	End time.Time
+	// AllDay is set for events that are dates rather than times.
+	AllDay bool
+	// Recurrence holds the RRULE, RDATE and EXDATE of a recurring event, and
+	// is nil for other events.
+	Recurrence *rrule.Set
+	// RecurrenceID is the start of the occurrence of a recurring event that
+	// the event stands for, and is zero for other events.
+	RecurrenceID time.Time
+	// Line is the line of the file on which the event begins.
+	Line int
+}
+
+// Days returns the first day of e and the day after its last day, at
+// midnight UTC, as seen in loc. All-day events cover the same days anywhere.
+func (e *Event) Days(loc *time.Location) (first, end time.Time) {
+	if e.AllDay {
+		first, end = dateOf(e.Start), dateOf(e.End)
+	} else {
+		first, end = dateOf(e.Start.In(loc)), dateOf(e.End.In(loc))
+		// An event that ends during a day includes that day.
+		if h, m, s := e.End.In(loc).Clock(); h+m+s > 0 {
+			end = end.AddDate(0, 0, 1)
+		}
+	}
+	if !end.After(first) {
+		end = first.AddDate(0, 0, 1)
+	}
+	return first, end
+}
+
+// overlaps reports whether e takes place between from and to.
+func (e *Event) overlaps(from, to time.Time) bool {
+	return e.Start.Before(to) && (e.End.After(from) || !e.Start.Before(from))
+}
+
+// dateOf returns the date of t at midnight UTC.
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Calendar is the content of an iCalendar file.
+type Calendar struct {
+	// Name is the name of the calendar, from X-WR-CALNAME.
+	Name string
+	// ProdID identifies the program that created the file.
+	ProdID string
+	// Events lists the events in the order of the file. Cancelled events
+	// are left out.
+	Events []*Event
+}
+
+// property is a content line such as "DTSTART;TZID=Europe/Berlin:20261017T090000".
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+// component is a component such as VEVENT with its properties.
+type component struct {
+	name       string
+	line       int
+	properties []property
+	children   []*component
+}
+
+// get returns the first property with the given name.
+func (c *component) get(name string) (property, bool) {
+	for _, p := range c.properties {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// Between returns the events of c that take place between from and to, in
+// order of their start. Recurring events are expanded into an event for each
+// occurrence, with RecurrenceID set to its start, unless another event with
+// the same UID stands for that occurrence.
+func (c *Calendar) Between(from, to time.Time) []*Event {
+	overridden := make(map[occurrence]bool)
+	for _, e := range c.Events {
+		if !e.RecurrenceID.IsZero() {
+			overridden[occurrence{e.UID, e.RecurrenceID.Unix()}] = true
+		}
+	}
+
+	var events []*Event
+	for _, e := range c.Events {
+		if e.Recurrence == nil || !e.RecurrenceID.IsZero() {
+			if e.overlaps(from, to) {
+				events = append(events, e)
+			}
+			continue
+		}
+		duration := e.End.Sub(e.Start)
+		for _, start := range e.Recurrence.Between(from.Add(-duration), to) {
+			if overridden[occurrence{e.UID, start.Unix()}] {
+				continue
+			}
+			o := *e
+			o.Start, o.End, o.RecurrenceID = start, start.Add(duration), start
+			if o.overlaps(from, to) {
+				events = append(events, &o)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+// occurrence identifies an occurrence of a recurring event by its UID and
+// start.
+type occurrence struct {
+	uid   string
+	start int64
+}
+
+// Parse reads an iCalendar file. It understands events with DTSTART, DTEND or
+// DURATION, all-day and multi-day events, recurring events with RRULE, RDATE,
+// EXDATE and RECURRENCE-ID, and time zones given by VTIMEZONE components or
+// by IANA names such as "Europe/Berlin". Times without a time zone are taken
+// to be local times. Errors give the line number.
+func Parse(r io.Reader) (*Calendar, error) {
+	root, err := parseComponents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Calendar{}
+	found := false
+	for _, vcalendar := range root.children {
+		if vcalendar.name != "VCALENDAR" {
+			continue
+		}
+		found = true
+		if p, ok := vcalendar.get("X-WR-CALNAME"); ok && c.Name == "" {
+			c.Name = unescape(p.value)
+		}
+		if p, ok := vcalendar.get("PRODID"); ok && c.ProdID == "" {
+			c.ProdID = p.value
+		}
+		zones := make(map[string]*timezone)
+		for _, child := range vcalendar.children {
+			if child.name != "VTIMEZONE" {
+				continue
+			}
+			z, err := parseTimezone(child)
+			if err != nil {
+				return nil, err
+			}
+			zones[z.tzid] = z
+		}
+		var cancelled []*Event
+		for _, child := range vcalendar.children {
+			if child.name != "VEVENT" {
+				continue
+			}
+			e, err := parseEvent(child, zones)
+			if status, _ := child.get("STATUS"); strings.EqualFold(status.value, "CANCELLED") {
+				if err == nil {
+					cancelled = append(cancelled, e)
+				}
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			c.Events = append(c.Events, e)
+		}
+		c.exclude(cancelled)
+	}
+	if !found {
+		return nil, errors.New("no VCALENDAR in file")
+	}
+	return c, nil
+}
+
+// exclude removes the cancelled occurrences of recurring events from their
+// recurrence.
+func (c *Calendar) exclude(cancelled []*Event) {
+	for _, o := range cancelled {
+		if o.RecurrenceID.IsZero() {
+			continue
+		}
+		for _, e := range c.Events {
+			if e.UID == o.UID && e.Recurrence != nil && e.RecurrenceID.IsZero() {
+				e.Recurrence.ExDates = append(e.Recurrence.ExDates, o.RecurrenceID)
+			}
+		}
+	}
+}
+
+// parseComponents reads the content lines of r into a tree of components
+// under an unnamed root.
+func parseComponents(r io.Reader) (*component, error) {
+	root := &component{}
+	stack := []*component{root}
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		p, err := parseProperty(l.text, l.number)
+		if err != nil {
+			return nil, err
+		}
+		top := stack[len(stack)-1]
+		switch p.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(p.value), line: p.line}
+			top.children = append(top.children, c)
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 1 {
+				return nil, errors.Errorf("line %d: END:%s without BEGIN:%s", p.line, p.value, p.value)
+			}
+			if !strings.EqualFold(p.value, top.name) {
+				return nil, errors.Errorf("line %d: END:%s, expected END:%s", p.line, p.value, top.name)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 1 {
+				return nil, errors.Errorf("line %d: %s outside of a component", p.line, p.name)
+			}
+			top.properties = append(top.properties, p)
+		}
+	}
+	if len(stack) > 1 {
+		top := stack[len(stack)-1]
+		return nil, errors.Errorf("line %d: BEGIN:%s without END:%s", top.line, top.name, top.name)
+	}
+	return root, nil
+}
+
+// contentLine is a content line after unfolding and the number of the line
+// it starts on.
+type contentLine struct {
+	text   string
+	number int
+}
+
+// unfold reads the lines of r, joining the lines that start with a space or
+// a tab to the line before.
+func unfold(r io.Reader) ([]contentLine, error) {
+	var lines []contentLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		switch {
+		case text == "":
+		case (text[0] == ' ' || text[0] == '\t') && len(lines) > 0:
+			lines[len(lines)-1].text += text[1:]
+		default:
+			lines = append(lines, contentLine{text: text, number: number})
+		}
+	}
+	return lines, errors.Wrap(scanner.Err(), "error reading iCalendar file")
+}
+
+// parseProperty parses a content line: a name, parameters separated by
+// semicolons and a value after a colon. Parameter values may be quoted.
+func parseProperty(s string, line int) (property, error) {
+	p := property{line: line}
+	end := strings.IndexAny(s, ";:")
+	if end <= 0 {
+		return p, errors.Errorf("line %d: invalid content line %q", line, s)
+	}
+	p.name, s = strings.ToUpper(s[:end]), s[end:]
+	for strings.HasPrefix(s, ";") {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return p, errors.Errorf("line %d: invalid parameter in %s", line, p.name)
+		}
+		name := strings.ToUpper(s[1:eq])
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			closing := strings.IndexByte(s[1:], '"')
+			if closing < 0 {
+				return p, errors.Errorf("line %d: unterminated quote in %s", line, p.name)
+			}
+			value, s = s[1:closing+1], s[closing+2:]
+		} else {
+			end := strings.IndexAny(s, ";:")
+			if end < 0 {
+				return p, errors.Errorf("line %d: missing value in %s", line, p.name)
+			}
+			value, s = s[:end], s[end:]
+		}
+		if p.params == nil {
+			p.params = make(map[string]string)
+		}
+		p.params[name] = value
+	}
+	value, ok := strings.CutPrefix(s, ":")
+	if !ok {
+		return p, errors.Errorf("line %d: missing value in %s", line, p.name)
+	}
+	p.value = value
+	return p, nil
+}
+
+// parseEvent reads a VEVENT component.
+func parseEvent(c *component, zones map[string]*timezone) (*Event, error) {
+	e := &Event{Line: c.line}
+	for _, p := range c.properties {
+		switch p.name {
+		case "UID":
+			e.UID = p.value
+		case "SUMMARY":
+			e.Summary = unescape(p.value)
+		case "LOCATION":
+			e.Location = unescape(p.value)
+		case "DESCRIPTION":
+			e.Description = unescape(p.value)
+		}
+	}
+
+	start, ok := c.get("DTSTART")
+	if !ok {
+		return nil, errors.Errorf("line %d: event without DTSTART", c.line)
+	}
+	var err error
+	if e.Start, e.AllDay, err = parseTime(start, zones); err != nil {
+		return nil, err
+	}
+
+	if end, ok := c.get("DTEND"); ok {
+		if e.End, _, err = parseTime(end, zones); err != nil {
+			return nil, err
+		}
+		if e.End.Before(e.Start) {
+			return nil, errors.Errorf("line %d: DTEND is before DTSTART", end.line)
+		}
+	} else if duration, ok := c.get("DURATION"); ok {
+		days, clock, err := parseDuration(duration.value)
+		if err != nil {
+			return nil, errors.Errorf("line %d: %s", duration.line, err)
+		}
+		e.End = e.Start.AddDate(0, 0, days).Add(clock)
+	} else if e.AllDay {
+		e.End = e.Start.AddDate(0, 0, 1)
+	} else {
+		e.End = e.Start
+	}
+
+	if id, ok := c.get("RECURRENCE-ID"); ok {
+		if e.RecurrenceID, _, err = parseTime(id, zones); err != nil {
+			return nil, err
+		}
+	}
+	if err := parseRecurrence(e, c, zones); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// parseRecurrence reads the RRULE, RDATE and EXDATE properties of an event
+// into its Recurrence.
+func parseRecurrence(e *Event, c *component, zones map[string]*timezone) error {
+	s := &rrule.Set{Start: e.Start}
+	for _, p := range c.properties {
+		switch p.name {
+		case "RRULE":
+			r, err := rrule.Parse(p.value)
+			if err != nil {
+				return errors.Errorf("line %d: %s in RRULE", p.line, err)
+			}
+			// An UNTIL without a time zone is in the time zone of the event.
+			for _, part := range strings.Split(strings.ToUpper(p.value), ";") {
+				if until, ok := strings.CutPrefix(part, "UNTIL="); ok && !strings.HasSuffix(until, "Z") {
+					r.Until = inLocation(r.Until, e.Start.Location())
+				}
+			}
+			s.Rules = append(s.Rules, r)
+		case "RDATE", "EXDATE":
+			times, err := parseTimes(p, zones)
+			if err != nil {
+				return err
+			}
+			if p.name == "RDATE" {
+				s.RDates = append(s.RDates, times...)
+			} else {
+				s.ExDates = append(s.ExDates, times...)
+			}
+		}
+	}
+	if len(s.Rules) > 0 || len(s.RDates) > 0 {
+		e.Recurrence = s
+	}
+	return nil
+}
+
+// parseTimes parses the comma-separated values of RDATE or EXDATE. A period
+// stands for its start.
+func parseTimes(p property, zones map[string]*timezone) ([]time.Time, error) {
+	if strings.EqualFold(p.params["VALUE"], "PERIOD") {
+		p.params = map[string]string{"TZID": p.params["TZID"]}
+	}
+	var times []time.Time
+	for _, value := range strings.Split(p.value, ",") {
+		value, _, _ = strings.Cut(value, "/")
+		t, _, err := parseTime(property{name: p.name, params: p.params, value: value, line: p.line}, zones)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// parseTime parses the value of a DATE or DATE-TIME property such as DTSTART.
+// It reports whether the value is a date.
+func parseTime(p property, zones map[string]*timezone) (time.Time, bool, error) {
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(p.value) == len("20060102") {
+		t, err := time.Parse("20060102", p.value)
+		if err != nil {
+			return time.Time{}, false, errors.Errorf("line %d: invalid date %q in %s", p.line, p.value, p.name)
+		}
+		return t, true, nil
+	}
+
+	value, utc := strings.CutSuffix(p.value, "Z")
+	wall, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return time.Time{}, false, errors.Errorf("line %d: invalid date and time %q in %s", p.line, p.value, p.name)
+	}
+	tzid := p.params["TZID"]
+	switch {
+	case utc:
+		return wall, false, nil
+	case tzid == "":
+		return inLocation(wall, time.Local), false, nil
+	}
+	if z, ok := zones[tzid]; ok {
+		return inLocation(wall, z.loc), false, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+	if err != nil {
+		return time.Time{}, false, errors.Errorf("line %d: unknown time zone %q in %s", p.line, tzid, p.name)
+	}
+	return inLocation(wall, loc), false, nil
+}
+
+// inLocation returns the wall clock time of wall, which is in UTC, in loc.
+func inLocation(wall time.Time, loc *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+}
+
+// parseDuration parses a DURATION value such as "P1D", "PT1H30M" or "P2W"
+// into days and a time of day, which are added separately so that days are
+// calendar days.
+func parseDuration(s string) (int, time.Duration, error) {
+	invalid := errors.Errorf("invalid duration %q", s)
+	sign := 1
+	rest := strings.TrimPrefix(s, "+")
+	if after, ok := strings.CutPrefix(rest, "-"); ok {
+		sign, rest = -1, after
+	}
+	rest, ok := strings.CutPrefix(rest, "P")
+	if !ok || rest == "" {
+		return 0, 0, invalid
+	}
+	var days int
+	var clock time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' && !inTime {
+			inTime, rest = true, rest[1:]
+			continue
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if end <= 0 {
+			return 0, 0, invalid
+		}
+		n, _ := strconv.Atoi(rest[:end])
+		switch unit := rest[end]; {
+		case unit == 'W' && !inTime:
+			days += 7 * n
+		case unit == 'D' && !inTime:
+			days += n
+		case unit == 'H' && inTime:
+			clock += time.Duration(n) * time.Hour
+		case unit == 'M' && inTime:
+			clock += time.Duration(n) * time.Minute
+		case unit == 'S' && inTime:
+			clock += time.Duration(n) * time.Second
+		default:
+			return 0, 0, invalid
+		}
+		rest = rest[end+1:]
+	}
+	return sign * days, time.Duration(sign) * clock, nil
+}
+
+// unescape replaces the escape sequences of TEXT values.
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
@@ -0,0 +1,510 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// vcalendar wraps lines in a VCALENDAR with CRLF line endings.
+func vcalendar(lines ...string) string {
+	lines = append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Example//Test//EN"}, lines...), "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// berlin is the VTIMEZONE of Central European Time as exported by calendar
+// applications.
+var berlin = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:W. Europe Standard Time",
+	"BEGIN:STANDARD",
+	"DTSTART:16010101T030000",
+	"TZOFFSETFROM:+0200",
+	"TZOFFSETTO:+0100",
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10",
+	"END:STANDARD",
+	"BEGIN:DAYLIGHT",
+	"DTSTART:16010101T020000",
+	"TZOFFSETFROM:+0100",
+	"TZOFFSETTO:+0200",
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3",
+	"END:DAYLIGHT",
+	"END:VTIMEZONE",
+}
+
+func TestParse(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		lines  []string
+		start  time.Time
+		end    time.Time
+		allDay bool
+	}{
+		{
+			name:   "all-day",
+			lines:  []string{"DTSTART;VALUE=DATE:20261017", "DTEND;VALUE=DATE:20261018"},
+			start:  time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+			allDay: true,
+		},
+		{
+			name:   "all-day without end",
+			lines:  []string{"DTSTART;VALUE=DATE:20261017"},
+			start:  time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+			allDay: true,
+		},
+		{
+			name:   "multi-day",
+			lines:  []string{"DTSTART;VALUE=DATE:20261228", "DURATION:P1W"},
+			start:  time.Date(2026, time.December, 28, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2027, time.January, 4, 0, 0, 0, 0, time.UTC),
+			allDay: true,
+		},
+		{
+			name:  "UTC",
+			lines: []string{"DTSTART:20261017T090000Z", "DTEND:20261017T103000Z"},
+			start: time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.October, 17, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "IANA time zone",
+			lines: []string{"DTSTART;TZID=America/New_York:20261017T090000", "DURATION:PT1H30M"},
+			start: time.Date(2026, time.October, 17, 9, 0, 0, 0, newYork),
+			end:   time.Date(2026, time.October, 17, 10, 30, 0, 0, newYork),
+		},
+		{
+			name:  "VTIMEZONE in summer time",
+			lines: []string{`DTSTART;TZID="W. Europe Standard Time":20261017T090000`, `DTEND;TZID="W. Europe Standard Time":20261017T100000`},
+			start: time.Date(2026, time.October, 17, 7, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.October, 17, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "VTIMEZONE in standard time",
+			lines: []string{`DTSTART;TZID=W. Europe Standard Time:20261025T090000`},
+			start: time.Date(2026, time.October, 25, 8, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.October, 25, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "VTIMEZONE in winter",
+			lines: []string{`DTSTART;TZID=W. Europe Standard Time:20260115T120000`},
+			start: time.Date(2026, time.January, 15, 11, 0, 0, 0, time.UTC),
+			end:   time.Date(2026, time.January, 15, 11, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{}, berlin...)
+			lines = append(lines, "BEGIN:VEVENT", "UID:1@example.com", "SUMMARY:Test")
+			lines = append(lines, tt.lines...)
+			lines = append(lines, "END:VEVENT")
+			c, err := Parse(strings.NewReader(vcalendar(lines...)))
+			assert.NoError(t, err)
+			if assert.Len(t, c.Events, 1) {
+				e := c.Events[0]
+				assert.True(t, tt.start.Equal(e.Start), "start %v, expected %v", e.Start, tt.start)
+				assert.True(t, tt.end.Equal(e.End), "end %v, expected %v", e.End, tt.end)
+				assert.Equal(t, tt.allDay, e.AllDay)
+			}
+		})
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	data := vcalendar(
+		"X-WR-CALNAME:Team",
+		"BEGIN:VEVENT",
+		"UID:1@example.com",
+		"SUMMARY:Sprint review\\, demo",
+		"  and retro",
+		"LOCATION:Room 1\\; 2nd floor",
+		"DESCRIPTION:Agenda:\\nDemo",
+		"DTSTART;VALUE=DATE:20261017",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Reminder",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@example.com",
+		"SUMMARY:Cancelled",
+		"STATUS:CANCELLED",
+		"DTSTART;VALUE=DATE:20261018",
+		"END:VEVENT",
+	)
+	c, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "Team", c.Name)
+	if assert.Len(t, c.Events, 1) {
+		e := c.Events[0]
+		assert.Equal(t, "1@example.com", e.UID)
+		assert.Equal(t, "Sprint review, demo and retro", e.Summary)
+		assert.Equal(t, "Room 1; 2nd floor", e.Location)
+		assert.Equal(t, "Agenda:\nDemo", e.Description)
+		assert.Equal(t, 5, e.Line)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "no calendar",
+			data:     "",
+			expected: "no VCALENDAR in file",
+		},
+		{
+			name:     "missing end",
+			data:     "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261017T090000Z\nEND:VCALENDAR\n",
+			expected: "line 4: END:VCALENDAR, expected END:VEVENT",
+		},
+		{
+			name:     "end without begin",
+			data:     "END:VCALENDAR\n",
+			expected: "line 1: END:VCALENDAR without BEGIN:VCALENDAR",
+		},
+		{
+			name:     "unterminated component",
+			data:     "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261017T090000Z\n",
+			expected: "line 2: BEGIN:VEVENT without END:VEVENT",
+		},
+		{
+			name:     "invalid content line",
+			data:     "BEGIN:VCALENDAR\nno colon\nEND:VCALENDAR\n",
+			expected: `line 2: invalid content line "no colon"`,
+		},
+		{
+			name:     "missing start",
+			data:     vcalendar("BEGIN:VEVENT", "SUMMARY:x", "END:VEVENT"),
+			expected: "line 4: event without DTSTART",
+		},
+		{
+			name:     "invalid date",
+			data:     vcalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20261317", "END:VEVENT"),
+			expected: `line 5: invalid date "20261317" in DTSTART`,
+		},
+		{
+			name:     "invalid time",
+			data:     vcalendar("BEGIN:VEVENT", "DTSTART:20261017T250000Z", "END:VEVENT"),
+			expected: `line 5: invalid date and time "20261017T250000Z" in DTSTART`,
+		},
+		{
+			name:     "unknown time zone",
+			data:     vcalendar("BEGIN:VEVENT", "DTSTART;TZID=Mars/Olympus_Mons:20261017T090000", "END:VEVENT"),
+			expected: `line 5: unknown time zone "Mars/Olympus_Mons" in DTSTART`,
+		},
+		{
+			name:     "end before start",
+			data:     vcalendar("BEGIN:VEVENT", "DTSTART:20261017T090000Z", "DTEND:20261017T080000Z", "END:VEVENT"),
+			expected: "line 6: DTEND is before DTSTART",
+		},
+		{
+			name:     "invalid duration",
+			data:     vcalendar("BEGIN:VEVENT", "DTSTART:20261017T090000Z", "DURATION:PT1D", "END:VEVENT"),
+			expected: `line 6: invalid duration "PT1D"`,
+		},
+		{
+			name:     "invalid offset",
+			data:     vcalendar("BEGIN:VTIMEZONE", "TZID:X", "BEGIN:STANDARD", "DTSTART:19700101T000000", "TZOFFSETTO:0100", "END:STANDARD", "END:VTIMEZONE"),
+			expected: `line 8: invalid offset "0100" in TZOFFSETTO`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.data))
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestEventDays(t *testing.T) {
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	tokyo := time.FixedZone("JST", 9*3600)
+	tests := []struct {
+		name  string
+		event Event
+		loc   *time.Location
+		first time.Time
+		end   time.Time
+	}{
+		{
+			name:  "all-day",
+			event: Event{Start: utc(time.October, 17, 0), End: utc(time.October, 20, 0), AllDay: true},
+			loc:   tokyo,
+			first: utc(time.October, 17, 0),
+			end:   utc(time.October, 20, 0),
+		},
+		{
+			name:  "timed",
+			event: Event{Start: utc(time.October, 17, 9), End: utc(time.October, 17, 10)},
+			loc:   time.UTC,
+			first: utc(time.October, 17, 0),
+			end:   utc(time.October, 18, 0),
+		},
+		{
+			name:  "timed in another time zone",
+			event: Event{Start: utc(time.October, 17, 20), End: utc(time.October, 17, 21)},
+			loc:   tokyo,
+			first: utc(time.October, 18, 0),
+			end:   utc(time.October, 19, 0),
+		},
+		{
+			name:  "overnight",
+			event: Event{Start: utc(time.October, 17, 20), End: utc(time.October, 18, 2)},
+			loc:   time.UTC,
+			first: utc(time.October, 17, 0),
+			end:   utc(time.October, 19, 0),
+		},
+		{
+			name:  "until midnight",
+			event: Event{Start: utc(time.October, 17, 20), End: utc(time.October, 18, 0)},
+			loc:   time.UTC,
+			first: utc(time.October, 17, 0),
+			end:   utc(time.October, 18, 0),
+		},
+		{
+			name:  "instant",
+			event: Event{Start: utc(time.October, 17, 0), End: utc(time.October, 17, 0)},
+			loc:   time.UTC,
+			first: utc(time.October, 17, 0),
+			end:   utc(time.October, 18, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, end := tt.event.Days(tt.loc)
+			assert.Equal(t, tt.first, first)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
+
+func TestCalendarBetween(t *testing.T) {
+	data := vcalendar(
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"SUMMARY:Standup",
+		"DTSTART;TZID=America/New_York:20261005T090000",
+		"DURATION:PT15M",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20261021T090000",
+		"EXDATE;TZID=America/New_York:20261012T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"SUMMARY:Standup (moved)",
+		"RECURRENCE-ID;TZID=America/New_York:20261014T090000",
+		"DTSTART;TZID=America/New_York:20261015T100000",
+		"DURATION:PT15M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID;TZID=America/New_York:20261019T090000",
+		"DTSTART;TZID=America/New_York:20261019T090000",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:review@example.com",
+		"SUMMARY:Sprint review",
+		"DTSTART;VALUE=DATE:20260925",
+		"DTEND;VALUE=DATE:20260928",
+		"RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+		"RDATE;VALUE=DATE:20261016",
+		"END:VEVENT",
+	)
+	c, err := Parse(strings.NewReader(data))
+	assert.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	ny := func(day, hour int) time.Time { return time.Date(2026, time.October, day, hour, 0, 0, 0, newYork).UTC() }
+	utc := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC) }
+
+	type occurrence struct {
+		summary string
+		start   time.Time
+	}
+	var occurrences []occurrence
+	for _, e := range c.Between(utc(time.September, 27), utc(time.November, 1)) {
+		occurrences = append(occurrences, occurrence{e.Summary, e.Start.UTC()})
+	}
+	assert.Equal(t, []occurrence{
+		{"Sprint review", utc(time.September, 25)},
+		{"Standup", ny(5, 9)},
+		{"Standup", ny(7, 9)},
+		{"Standup (moved)", ny(15, 10)},
+		{"Sprint review", utc(time.October, 16)},
+		{"Standup", ny(21, 9)},
+		{"Sprint review", utc(time.October, 30)},
+	}, occurrences)
+
+	events := c.Between(utc(time.October, 7), utc(time.October, 8))
+	if assert.Len(t, events, 1) {
+		assert.True(t, ny(7, 9).Equal(events[0].RecurrenceID))
+		assert.Equal(t, 15*time.Minute, events[0].End.Sub(events[0].Start))
+	}
+}
+
+func TestCalendarBetweenAcrossDST(t *testing.T) {
+	lines := append([]string{}, berlin...)
+	lines = append(lines,
+		"BEGIN:VEVENT",
+		"UID:late@example.com",
+		"SUMMARY:Late call",
+		"DTSTART;TZID=W. Europe Standard Time:20250106T233000",
+		"DURATION:PT30M",
+		"RRULE:FREQ=WEEKLY",
+		"EXDATE;TZID=W. Europe Standard Time:20250714T233000",
+		"END:VEVENT",
+	)
+	c, err := Parse(strings.NewReader(vcalendar(lines...)))
+	assert.NoError(t, err)
+
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "standard time",
+			from:     utc(time.January, 13, 0, 0),
+			to:       utc(time.January, 21, 0, 0),
+			expected: []time.Time{utc(time.January, 13, 22, 30), utc(time.January, 20, 22, 30)},
+		},
+		{
+			name:     "summer time",
+			from:     utc(time.July, 7, 0, 0),
+			to:       utc(time.July, 22, 0, 0),
+			expected: []time.Time{utc(time.July, 7, 21, 30), utc(time.July, 21, 21, 30)},
+		},
+		{
+			name:     "change to summer time",
+			from:     utc(time.March, 24, 0, 0),
+			to:       utc(time.April, 1, 0, 0),
+			expected: []time.Time{utc(time.March, 24, 22, 30), utc(time.March, 31, 21, 30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var starts []time.Time
+			for _, e := range c.Between(tt.from, tt.to) {
+				starts = append(starts, e.Start.UTC())
+				assert.Equal(t, time.Monday, e.Start.Weekday(), "%v should be a Monday", e.Start)
+				assert.Equal(t, 23, e.Start.Hour())
+				assert.Equal(t, 30*time.Minute, e.End.Sub(e.Start))
+			}
+			assert.Equal(t, tt.expected, starts)
+		})
+	}
+}
+
+func TestTimezoneLocation(t *testing.T) {
+	// The start of summer time in the United States up to 2006, the first
+	// Sunday in April, written with BYMONTHDAY, which has no POSIX TZ form.
+	eastern := []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Eastern",
+		"BEGIN:STANDARD",
+		"DTSTART:19671029T020000",
+		"TZOFFSETFROM:-0400",
+		"TZOFFSETTO:-0500",
+		"TZNAME:EST",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:19870405T020000",
+		"TZOFFSETFROM:-0500",
+		"TZOFFSETTO:-0400",
+		"TZNAME:EDT",
+		"RRULE:FREQ=YEARLY;BYMONTH=4;BYMONTHDAY=1,2,3,4,5,6,7;BYDAY=SU",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+	}
+	tests := []struct {
+		name     string
+		zone     []string
+		footer   string
+		wall     time.Time
+		offset   int
+		daylight bool
+	}{
+		{name: "winter", zone: berlin, wall: time.Date(2026, time.January, 15, 12, 0, 0, 0, time.UTC), offset: 3600},
+		{name: "summer", zone: berlin, wall: time.Date(2026, time.July, 15, 12, 0, 0, 0, time.UTC), offset: 7200, daylight: true},
+		{name: "before the first onset", zone: berlin, wall: time.Date(1600, time.July, 15, 12, 0, 0, 0, time.UTC), offset: 3600},
+		{name: "winter after the listed transitions", zone: berlin, wall: time.Date(2150, time.January, 15, 12, 0, 0, 0, time.UTC), offset: 3600},
+		{name: "summer after the listed transitions", zone: berlin, wall: time.Date(2150, time.July, 15, 12, 0, 0, 0, time.UTC), offset: 7200, daylight: true},
+		{name: "summer without a footer", zone: eastern, wall: time.Date(2150, time.April, 10, 12, 0, 0, 0, time.UTC), offset: -14400, daylight: true},
+		{name: "winter without a footer", zone: eastern, wall: time.Date(2150, time.April, 1, 12, 0, 0, 0, time.UTC), offset: -18000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := inLocation(tt.wall, testTimezone(t, tt.zone).loc)
+			_, offset := d.Zone()
+			assert.Equal(t, tt.offset, offset)
+			assert.Equal(t, tt.daylight, d.IsDST())
+		})
+	}
+}
+
+// testTimezone parses a VTIMEZONE component.
+func testTimezone(t *testing.T, lines []string) *timezone {
+	root, err := parseComponents(strings.NewReader(vcalendar(lines...)))
+	assert.NoError(t, err)
+	z, err := parseTimezone(root.children[0].children[0])
+	assert.NoError(t, err)
+	return z
+}
+
+func TestTimezoneFooter(t *testing.T) {
+	assert.Equal(t, "<W. Europe Standard Time>-1:00:00<W. Europe Standard Time>-2:00:00,M3.5.0/2:00:00,M10.5.0/3:00:00", testTimezone(t, berlin).footer())
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected string
+	}{
+		{
+			name:     "invalid rule",
+			lines:    []string{"RRULE:FREQ=DAILY;COUNT=0"},
+			expected: `line 6: invalid COUNT "0" in RRULE`,
+		},
+		{
+			name:     "invalid exception",
+			lines:    []string{"EXDATE:20261017T090000Z,20261018T9"},
+			expected: `line 6: invalid date and time "20261018T9" in EXDATE`,
+		},
+		{
+			name:     "invalid recurrence ID",
+			lines:    []string{"RECURRENCE-ID;VALUE=DATE:2026"},
+			expected: `line 6: invalid date "2026" in RECURRENCE-ID`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VEVENT", "DTSTART:20261017T090000Z"}, tt.lines...)
+			_, err := Parse(strings.NewReader(vcalendar(append(lines, "END:VEVENT")...)))
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
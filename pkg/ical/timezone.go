@@ -0,0 +1,281 @@
+package ical
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mojotx/cal/pkg/rrule"
+	"github.com/pkg/errors"
+)
+
+// timezone is a time zone defined by a VTIMEZONE component.
+type timezone struct {
+	tzid        string
+	observances []observance
+	loc         *time.Location
+}
+
+// observance is a STANDARD or DAYLIGHT component of a VTIMEZONE: an offset
+// from UTC that takes effect at the onsets given by DTSTART and RRULE.
+type observance struct {
+	name       string
+	start      time.Time
+	offsetFrom int
+	offsetTo   int
+	daylight   bool
+	rule       *rrule.Rule
+}
+
+// parseTimezone reads a VTIMEZONE component.
+func parseTimezone(c *component) (*timezone, error) {
+	tzid, ok := c.get("TZID")
+	if !ok {
+		return nil, errors.Errorf("line %d: time zone without TZID", c.line)
+	}
+	z := &timezone{tzid: tzid.value}
+	for _, child := range c.children {
+		if child.name != "STANDARD" && child.name != "DAYLIGHT" {
+			continue
+		}
+		obs := observance{name: tzid.value, daylight: child.name == "DAYLIGHT"}
+		start, ok := child.get("DTSTART")
+		if !ok {
+			return nil, errors.Errorf("line %d: %s without DTSTART", child.line, child.name)
+		}
+		var err error
+		if obs.start, err = time.Parse("20060102T150405", start.value); err != nil {
+			return nil, errors.Errorf("line %d: invalid date and time %q in DTSTART", start.line, start.value)
+		}
+		for _, p := range child.properties {
+			switch p.name {
+			case "TZNAME":
+				obs.name = p.value
+			case "TZOFFSETFROM":
+				obs.offsetFrom, err = parseOffset(p)
+			case "TZOFFSETTO":
+				obs.offsetTo, err = parseOffset(p)
+			case "RRULE":
+				if obs.rule, err = rrule.Parse(p.value); err != nil {
+					err = errors.Errorf("line %d: %s in RRULE", p.line, err)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		z.observances = append(z.observances, obs)
+	}
+	if len(z.observances) == 0 {
+		return nil, errors.Errorf("line %d: time zone %q without STANDARD or DAYLIGHT", c.line, z.tzid)
+	}
+	var err error
+	if z.loc, err = z.location(); err != nil {
+		return nil, errors.Wrapf(err, "line %d", c.line)
+	}
+	return z, nil
+}
+
+// parseOffset parses a UTC offset such as "+0100" or "-0330" into seconds.
+func parseOffset(p property) (int, error) {
+	s := p.value
+	if (len(s) != 5 && len(s) != 7) || (s[0] != '+' && s[0] != '-') {
+		return 0, errors.Errorf("line %d: invalid offset %q in %s", p.line, s, p.name)
+	}
+	var fields [3]int
+	for i := 0; 1+2*i < len(s); i++ {
+		n, err := strconv.Atoi(s[1+2*i : 3+2*i])
+		if err != nil {
+			return 0, errors.Errorf("line %d: invalid offset %q in %s", p.line, s, p.name)
+		}
+		fields[i] = n
+	}
+	offset := fields[0]*3600 + fields[1]*60 + fields[2]
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+// maxYear is the last year for which the transitions of a time zone are
+// computed, the last year cal shows.
+const maxYear = 9999
+
+// footerYear is the last year whose transitions are listed when a POSIX TZ
+// string describes the later ones, as zic does.
+const footerYear = 2037
+
+// onsets returns the times at which the observance takes effect, up to the
+// end of the year until.
+func (obs *observance) onsets(until int) []time.Time {
+	// The onsets are computed in the offset they change from, so that UNTIL,
+	// which is in UTC, applies at the right time.
+	zone := time.FixedZone(obs.name, obs.offsetFrom)
+	start := inLocation(obs.start, zone)
+	if obs.rule == nil {
+		return []time.Time{start}
+	}
+	return obs.rule.Between(start, start, time.Date(until+1, time.January, 1, 0, 0, 0, 0, zone))
+}
+
+// location returns the time zone as a time.Location whose transitions are
+// the onsets of the observances, so that times keep to the offset of the
+// observance in effect also as recurrences move them across one. Before the
+// first onset, the offset it changes from applies.
+func (z *timezone) location() (*time.Location, error) {
+	if len(z.observances) > 255 {
+		return nil, errors.Errorf("time zone %q has too many observances", z.tzid)
+	}
+	first := &z.observances[0]
+	for i := range z.observances {
+		if z.observances[i].start.Before(first.start) {
+			first = &z.observances[i]
+		}
+	}
+	footer := z.footer()
+	until := maxYear
+	if footer != "" {
+		until = footerYear
+	}
+
+	// Type 0 is the offset before the first onset and type i+1 the offset of
+	// observance i.
+	types := []zoneType{{name: first.name, offset: first.offsetFrom}}
+	var transitions []transition
+	for i := range z.observances {
+		obs := &z.observances[i]
+		types = append(types, zoneType{name: obs.name, offset: obs.offsetTo, daylight: obs.daylight})
+		for _, t := range obs.onsets(until) {
+			transitions = append(transitions, transition{at: t.Unix(), zone: uint8(i + 1)})
+		}
+	}
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].at < transitions[j].at })
+	return time.LoadLocationFromTZData(z.tzid, tzdata(types, transitions, footer))
+}
+
+// footer returns the POSIX TZ string that continues the transitions after
+// footerYear, e.g. "<CET>-1<CEST>-2,M3.5.0/2:00:00,M10.5.0/3:00:00". It is
+// empty unless the time zone ends in a standard and a daylight observance
+// that take effect every year on the nth or last weekday of a month, as most
+// do.
+func (z *timezone) footer() string {
+	var std, dst *observance
+	for i := range z.observances {
+		obs := &z.observances[i]
+		if obs.rule == nil || obs.rule.Count != 0 || !obs.rule.Until.IsZero() {
+			continue
+		}
+		if obs.start.Year() > footerYear || (obs.daylight && dst != nil) || (!obs.daylight && std != nil) {
+			return ""
+		}
+		if obs.daylight {
+			dst = obs
+		} else {
+			std = obs
+		}
+	}
+	if std == nil || dst == nil {
+		return ""
+	}
+	start, ok := dst.posixRule()
+	if !ok {
+		return ""
+	}
+	end, ok := std.posixRule()
+	if !ok {
+		return ""
+	}
+	return posixName(std.name) + posixOffset(std.offsetTo) + posixName(dst.name) + posixOffset(dst.offsetTo) + "," + start + "," + end
+}
+
+// posixRule returns the onsets of the observance as a rule of a POSIX TZ
+// string, "Mm.w.d/time", if it has that form.
+func (obs *observance) posixRule() (string, bool) {
+	r := obs.rule
+	if r.Freq != rrule.Yearly || r.Interval > 1 || len(r.ByMonth) != 1 || len(r.ByDay) != 1 || len(r.ByMonthDay) != 0 || len(r.BySetPos) != 0 {
+		return "", false
+	}
+	// Week 5 is the last one.
+	week := r.ByDay[0].N
+	switch {
+	case week == -1:
+		week = 5
+	case week < 1 || week > 4:
+		return "", false
+	}
+	h, m, sec := obs.start.Clock()
+	return fmt.Sprintf("M%d.%d.%d/%d:%02d:%02d", r.ByMonth[0], week, r.ByDay[0].Weekday, h, m, sec), true
+}
+
+// posixName quotes the name of an offset for a POSIX TZ string.
+func posixName(name string) string {
+	return "<" + strings.ReplaceAll(name, ">", "") + ">"
+}
+
+// posixOffset writes an offset for a POSIX TZ string, which counts west of
+// UTC rather than east.
+func posixOffset(offset int) string {
+	sign := "-"
+	if offset <= 0 {
+		sign, offset = "", -offset
+	}
+	return fmt.Sprintf("%s%d:%02d:%02d", sign, offset/3600, offset/60%60, offset%60)
+}
+
+// zoneType is a local time type of TZif data: an offset from UTC in seconds.
+type zoneType struct {
+	name     string
+	offset   int
+	daylight bool
+}
+
+// transition is a change to the local time type zone at the Unix time at.
+type transition struct {
+	at   int64
+	zone uint8
+}
+
+// tzdata encodes a time zone in the TZif format of RFC 8536, which
+// time.LoadLocationFromTZData reads. It writes version 2 with 64-bit times
+// and leaves the version 1 block, which readers of version 2 skip, empty.
+// The footer applies after the last transition.
+func tzdata(types []zoneType, transitions []transition, footer string) []byte {
+	var names []byte
+	nameIndex := make(map[string]int)
+	for _, t := range types {
+		if _, ok := nameIndex[t.name]; !ok {
+			nameIndex[t.name] = len(names)
+			names = append(append(names, t.name...), 0)
+		}
+	}
+
+	header := func(b []byte, counts ...int) []byte {
+		b = append(b, "TZif2"...)
+		b = append(b, make([]byte, 15)...)
+		for _, n := range counts {
+			b = binary.BigEndian.AppendUint32(b, uint32(n))
+		}
+		return b
+	}
+	b := header(nil, 0, 0, 0, 0, 0, 0)
+	b = header(b, 0, 0, 0, len(transitions), len(types), len(names))
+	for _, t := range transitions {
+		b = binary.BigEndian.AppendUint64(b, uint64(t.at))
+	}
+	for _, t := range transitions {
+		b = append(b, t.zone)
+	}
+	for _, t := range types {
+		b = binary.BigEndian.AppendUint32(b, uint32(int32(t.offset)))
+		var dst byte
+		if t.daylight {
+			dst = 1
+		}
+		b = append(b, dst, byte(nameIndex[t.name]))
+	}
+	b = append(b, names...)
+	return append(b, "\n"+footer+"\n"...)
+}
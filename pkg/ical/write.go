@@ -0,0 +1,119 @@
+package ical
+
+import (
+	"bufio"
+	"cmp"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// defaultProdID identifies the files written by Write if the calendar does
+// not name the program that created it.
+const defaultProdID = "-//mojotx//cal//EN"
+
+// now returns the time written as the DTSTAMP of events. Tests replace it.
+var now = time.Now
+
+// maxLineLength is the length in bytes after which content lines are folded.
+const maxLineLength = 75
+
+// Write writes c as an iCalendar file. Every event needs a UID, which
+// calendar applications use to update the event when the file is imported
+// again. All-day events are written as dates and other events in UTC. A
+// calendar without events is an error, as iCalendar files need at least one
+// component.
+func Write(w io.Writer, c *Calendar) error {
+	if len(c.Events) == 0 {
+		return errors.New("calendar has no events")
+	}
+	for _, e := range c.Events {
+		if e.UID == "" {
+			return errors.Errorf("event %q has no UID", e.Summary)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", cmp.Or(c.ProdID, defaultProdID))
+	line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+	stamp := now().UTC().Format("20060102T150405Z")
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp)
+		writeTime(bw, "DTSTART", e.Start, e.AllDay)
+		if e.End.After(e.Start) {
+			writeTime(bw, "DTEND", e.End, e.AllDay)
+		}
+		if !e.RecurrenceID.IsZero() {
+			writeTime(bw, "RECURRENCE-ID", e.RecurrenceID, e.AllDay)
+		}
+		if e.Recurrence != nil {
+			for _, r := range e.Recurrence.Rules {
+				line("RRULE", r.String())
+			}
+			for _, t := range e.Recurrence.RDates {
+				writeTime(bw, "RDATE", t, e.AllDay)
+			}
+			for _, t := range e.Recurrence.ExDates {
+				writeTime(bw, "EXDATE", t, e.AllDay)
+			}
+		}
+		for _, p := range []struct{ name, value string }{
+			{"SUMMARY", e.Summary},
+			{"LOCATION", e.Location},
+			{"DESCRIPTION", e.Description},
+		} {
+			if p.value != "" {
+				line(p.name, escape(p.value))
+			}
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return errors.Wrap(bw.Flush(), "error writing iCalendar file")
+}
+
+// writeTime writes a DATE property for all-day events and a DATE-TIME
+// property in UTC for others.
+func writeTime(w *bufio.Writer, name string, t time.Time, allDay bool) {
+	if allDay {
+		writeFolded(w, name+";VALUE=DATE:"+t.Format("20060102"))
+		return
+	}
+	writeFolded(w, name+":"+t.UTC().Format("20060102T150405Z"))
+}
+
+// writeFolded writes a content line, folding it into lines of at most
+// maxLineLength bytes that continue with a space. Characters are not split.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		// The space that starts a continuation line counts towards its length.
+		s, limit = s[cut:], maxLineLength-1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+// escape escapes the characters of TEXT values that unescape reads back.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,103 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mojotx/cal/pkg/rrule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	sprints, err := rrule.Parse("FREQ=WEEKLY;INTERVAL=2;COUNT=3")
+	assert.NoError(t, err)
+	start := time.Date(2026, time.December, 25, 0, 0, 0, 0, time.UTC)
+	c := &Calendar{
+		Name: "Holidays, US",
+		Events: []*Event{
+			{UID: "1@example.com", Summary: "Christmas Day", Description: "Federal holiday; offices closed", Start: start, End: start.AddDate(0, 0, 1), AllDay: true},
+			{UID: "2@example.com", Summary: "Sprint", Start: start, End: start.AddDate(0, 0, 14), AllDay: true, Recurrence: &rrule.Set{Start: start, Rules: []*rrule.Rule{sprints}}},
+			{UID: "3@example.com", Summary: "Call", Start: time.Date(2026, time.October, 17, 9, 0, 0, 0, time.FixedZone("EDT", -4*3600)), End: time.Date(2026, time.October, 17, 14, 0, 0, 0, time.UTC)},
+		},
+	}
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, c))
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//mojotx//cal//EN",
+		"CALSCALE:GREGORIAN",
+		`X-WR-CALNAME:Holidays\, US`,
+		"BEGIN:VEVENT",
+		"UID:1@example.com",
+		"DTSTAMP:20261017T120000Z",
+		"DTSTART;VALUE=DATE:20261225",
+		"DTEND;VALUE=DATE:20261226",
+		"SUMMARY:Christmas Day",
+		`DESCRIPTION:Federal holiday\; offices closed`,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@example.com",
+		"DTSTAMP:20261017T120000Z",
+		"DTSTART;VALUE=DATE:20261225",
+		"DTEND;VALUE=DATE:20270108",
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=3",
+		"SUMMARY:Sprint",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:3@example.com",
+		"DTSTAMP:20261017T120000Z",
+		"DTSTART:20261017T130000Z",
+		"DTEND:20261017T140000Z",
+		"SUMMARY:Call",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), b.String())
+
+	// What Write writes, Parse reads back.
+	parsed, err := Parse(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Name, parsed.Name)
+	if assert.Len(t, parsed.Events, 3) {
+		assert.Equal(t, c.Events[0].Description, parsed.Events[0].Description)
+		assert.Len(t, parsed.Events[1].Recurrence.Between(start, start.AddDate(1, 0, 0)), 3)
+		assert.True(t, c.Events[2].Start.Equal(parsed.Events[2].Start))
+	}
+}
+
+func TestWriteFolding(t *testing.T) {
+	summary := strings.Repeat("Ünïcödé ", 20)
+	c := &Calendar{Events: []*Event{{UID: "1", Summary: summary, Start: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC), AllDay: true}}}
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, c))
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+		assert.True(t, utf8.ValidString(line), "line %q", line)
+	}
+
+	parsed, err := Parse(&b)
+	assert.NoError(t, err)
+	if assert.Len(t, parsed.Events, 1) {
+		assert.Equal(t, summary, parsed.Events[0].Summary)
+	}
+}
+
+func TestWriteWithoutUID(t *testing.T) {
+	var b bytes.Buffer
+	err := Write(&b, &Calendar{Events: []*Event{{Summary: "Christmas Day"}}})
+	assert.EqualError(t, err, `event "Christmas Day" has no UID`)
+	assert.Empty(t, b.String())
+}
+
+func TestWriteWithoutEvents(t *testing.T) {
+	var b bytes.Buffer
+	assert.EqualError(t, Write(&b, &Calendar{Name: "Holidays"}), "calendar has no events")
+	assert.Empty(t, b.String())
+}
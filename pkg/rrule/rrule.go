@@ -0,0 +1,552 @@
+// Package rrule expands the recurrence rules of iCalendar (RFC 5545), such as
+// "FREQ=MONTHLY;BYDAY=-1FR", into the times of their occurrences.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frequency is how often a rule repeats.
+type Frequency int
+
+const (
+	// Daily repeats every day.
+	Daily Frequency = iota
+	// Weekly repeats every week.
+	Weekly
+	// Monthly repeats every month.
+	Monthly
+	// Yearly repeats every year.
+	Yearly
+)
+
+// frequencies holds the names of the frequencies in rules.
+var frequencies = [...]string{Daily: "DAILY", Weekly: "WEEKLY", Monthly: "MONTHLY", Yearly: "YEARLY"}
+
+// String returns the name of f in rules, e.g. "MONTHLY".
+func (f Frequency) String() string {
+	if f < Daily || f > Yearly {
+		return "UNKNOWN"
+	}
+	return frequencies[f]
+}
+
+// weekdayCodes holds the codes of the weekdays in rules, Sunday first.
+var weekdayCodes = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Weekday is a day of the week in BYDAY, such as every Monday or the last
+// Friday of a month.
+type Weekday struct {
+	Weekday time.Weekday
+	// N selects the nth weekday of the month, or of the year for yearly
+	// rules without BYMONTH. Negative values count from the end, and zero
+	// selects every such weekday.
+	N int
+}
+
+// String returns w as written in rules, e.g. "MO" or "-1FR".
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdayCodes[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + weekdayCodes[w.Weekday]
+}
+
+// Rule is a recurrence rule (RRULE).
+type Rule struct {
+	// Freq is how often the rule repeats.
+	Freq Frequency
+	// Interval repeats the rule every Interval days, weeks, months or
+	// years. Zero means 1.
+	Interval int
+	// Count limits the number of occurrences, including the start. Zero
+	// means no limit.
+	Count int
+	// Until is the last time an occurrence may start. Zero means no limit.
+	Until time.Time
+	// ByMonth limits the occurrences to the given months.
+	ByMonth []time.Month
+	// ByMonthDay lists days of the month, counting from the end if negative.
+	ByMonthDay []int
+	// ByDay lists weekdays.
+	ByDay []Weekday
+	// BySetPos selects occurrences by their position within each period,
+	// counting from the end if negative.
+	BySetPos []int
+	// WeekStart is the first day of the week, which matters for weekly
+	// rules with an interval. Parse sets it to Monday unless WKST says
+	// otherwise.
+	WeekStart time.Weekday
+}
+
+// Parse parses a recurrence rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// It supports FREQ of DAILY, WEEKLY, MONTHLY and YEARLY with INTERVAL, COUNT,
+// UNTIL, BYMONTH, BYMONTHDAY, BYDAY, BYSETPOS and WKST. An UNTIL without a
+// time of day is the end of that day in UTC, and one without a time zone is
+// taken to be in UTC.
+func Parse(s string) (*Rule, error) {
+	r := &Rule{WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(strings.TrimPrefix(s, "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		if !ok || value == "" {
+			return nil, errors.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("%s given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq, err = parseFrequency(value)
+		case "INTERVAL":
+			r.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			r.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYMONTH":
+			err = parseList(name, value, func(s string) error {
+				m, err := parseNumber(name, s, 1, 12, false)
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+				return err
+			})
+		case "BYMONTHDAY":
+			err = parseList(name, value, func(s string) error {
+				d, err := parseNumber(name, s, 1, 31, true)
+				r.ByMonthDay = append(r.ByMonthDay, d)
+				return err
+			})
+		case "BYDAY":
+			err = parseList(name, value, func(s string) error {
+				w, err := parseWeekday(s)
+				r.ByDay = append(r.ByDay, w)
+				return err
+			})
+		case "BYSETPOS":
+			err = parseList(name, value, func(s string) error {
+				pos, err := parseNumber(name, s, 1, 366, true)
+				r.BySetPos = append(r.BySetPos, pos)
+				return err
+			})
+		case "WKST":
+			var w Weekday
+			if w, err = parseWeekday(value); err == nil && w.N != 0 {
+				err = errors.Errorf("invalid WKST %q", value)
+			}
+			r.WeekStart = w.Weekday
+		case "BYSECOND", "BYMINUTE", "BYHOUR", "BYYEARDAY", "BYWEEKNO":
+			return nil, errors.Errorf("%s is not supported", name)
+		default:
+			return nil, errors.Errorf("unknown rule part %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !seen["FREQ"] {
+		return nil, errors.Errorf("rule %q has no FREQ", s)
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// validate checks the combinations of rule parts that RFC 5545 rules out.
+func (r *Rule) validate() error {
+	switch {
+	case r.Count != 0 && !r.Until.IsZero():
+		return errors.New("COUNT and UNTIL cannot both be given")
+	case r.Freq == Weekly && len(r.ByMonthDay) > 0:
+		return errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	case len(r.BySetPos) > 0 && len(r.ByMonth)+len(r.ByMonthDay)+len(r.ByDay) == 0:
+		return errors.New("BYSETPOS needs another BY rule part")
+	}
+	for _, w := range r.ByDay {
+		if w.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return errors.Errorf("BYDAY %s needs FREQ=MONTHLY or FREQ=YEARLY", w)
+		}
+	}
+	return nil
+}
+
+// parseFrequency parses the value of FREQ.
+func parseFrequency(s string) (Frequency, error) {
+	for f, name := range frequencies {
+		if strings.EqualFold(s, name) {
+			return Frequency(f), nil
+		}
+	}
+	switch strings.ToUpper(s) {
+	case "SECONDLY", "MINUTELY", "HOURLY":
+		return 0, errors.Errorf("FREQ=%s is not supported", strings.ToUpper(s))
+	}
+	return 0, errors.Errorf("invalid FREQ %q", s)
+}
+
+// parsePositive parses a number greater than zero.
+func parsePositive(name, s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, errors.Errorf("invalid %s %q", name, s)
+	}
+	return n, nil
+}
+
+// parseNumber parses a number from min to max, or from -max to -min if
+// negative is set.
+func parseNumber(name, s string, min, max int, negative bool) (int, error) {
+	n, err := strconv.Atoi(s)
+	abs := n
+	if n < 0 && negative {
+		abs = -n
+	}
+	if err != nil || abs < min || abs > max {
+		return 0, errors.Errorf("invalid %s %q", name, s)
+	}
+	return n, nil
+}
+
+// parseList calls parse for each element of a comma-separated list.
+func parseList(name, s string, parse func(string) error) error {
+	for _, element := range strings.Split(s, ",") {
+		if err := parse(element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseWeekday parses a BYDAY element such as "MO" or "-1FR".
+func parseWeekday(s string) (Weekday, error) {
+	invalid := errors.Errorf("invalid weekday %q", s)
+	if len(s) < 2 {
+		return Weekday{}, invalid
+	}
+	code, prefix := strings.ToUpper(s[len(s)-2:]), s[:len(s)-2]
+	w := Weekday{Weekday: -1}
+	for i, c := range weekdayCodes {
+		if c == code {
+			w.Weekday = time.Weekday(i)
+		}
+	}
+	if w.Weekday < 0 {
+		return Weekday{}, invalid
+	}
+	if prefix != "" {
+		n, err := parseNumber("weekday", strings.TrimPrefix(prefix, "+"), 1, 53, true)
+		if err != nil {
+			return Weekday{}, invalid
+		}
+		w.N = n
+	}
+	return w, nil
+}
+
+// parseUntil parses the value of UNTIL, a date or a date and time.
+func parseUntil(s string) (time.Time, error) {
+	if t, err := time.Parse("20060102", s); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	if t, err := time.Parse("20060102T150405", strings.TrimSuffix(s, "Z")); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.Errorf("invalid UNTIL %q", s)
+}
+
+// String returns r as accepted by Parse.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	list := func(name string, n int, element func(i int) string) {
+		if n == 0 {
+			return
+		}
+		elements := make([]string, n)
+		for i := range elements {
+			elements[i] = element(i)
+		}
+		parts = append(parts, name+"="+strings.Join(elements, ","))
+	}
+	list("BYMONTH", len(r.ByMonth), func(i int) string { return strconv.Itoa(int(r.ByMonth[i])) })
+	list("BYMONTHDAY", len(r.ByMonthDay), func(i int) string { return strconv.Itoa(r.ByMonthDay[i]) })
+	list("BYDAY", len(r.ByDay), func(i int) string { return r.ByDay[i].String() })
+	list("BYSETPOS", len(r.BySetPos), func(i int) string { return strconv.Itoa(r.BySetPos[i]) })
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCodes[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// maxYear ends the expansion of rules that never reach their end.
+const maxYear = 9999
+
+// Between returns the occurrences of r for an event that starts at start,
+// from from up to but not including to, in order. As in iCalendar, start is
+// always the first occurrence and counts towards Count. The occurrences have
+// the time of day and the location of start.
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	r.each(start, to, func(t time.Time) {
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+	})
+	return occurrences
+}
+
+// Synchronized reports whether the rule itself selects the day of start, as
+// iCalendar expects of DTSTART. Between counts start as the first occurrence
+// either way.
+func (r *Rule) Synchronized(start time.Time) bool {
+	first := dateOf(start)
+	_, days := r.period(first, 0)
+	for _, d := range r.setPositions(days) {
+		if d.Equal(first) {
+			return true
+		}
+	}
+	return false
+}
+
+// each calls yield for the occurrences of r that start before end, in order.
+func (r *Rule) each(start, end time.Time, yield func(time.Time)) {
+	n := 0
+	// emit yields t and reports whether more occurrences may follow.
+	emit := func(t time.Time) bool {
+		if !t.Before(end) || (!r.Until.IsZero() && t.After(r.Until)) {
+			return false
+		}
+		yield(t)
+		n++
+		return r.Count == 0 || n < r.Count
+	}
+	if !emit(start) {
+		return
+	}
+
+	hour, minute, second := start.Clock()
+	first := dateOf(start)
+	for k := 0; ; k++ {
+		period, days := r.period(first, k)
+		if period.Year() > maxYear || !inLocation(period, 0, 0, 0, start).Before(end) ||
+			(!r.Until.IsZero() && inLocation(period, 0, 0, 0, start).After(r.Until)) {
+			return
+		}
+		for _, d := range r.setPositions(days) {
+			// Days of the first period before the start are not occurrences.
+			t := inLocation(d, hour, minute, second, start)
+			if !t.After(start) {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// period returns the first day of the kth period of r after the one
+// containing first, and the days of the rule in it in order.
+func (r *Rule) period(first time.Time, k int) (time.Time, []time.Time) {
+	interval := max(r.Interval, 1)
+	switch r.Freq {
+	case Daily:
+		day := first.AddDate(0, 0, k*interval)
+		if r.matchesMonth(day) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			return day, []time.Time{day}
+		}
+		return day, nil
+
+	case Weekly:
+		weekStart := first.AddDate(0, 0, -((int(first.Weekday())-int(r.WeekStart)+7)%7)+7*k*interval)
+		var days []time.Time
+		for i := range 7 {
+			day := weekStart.AddDate(0, 0, i)
+			weekday := len(r.ByDay) == 0 && day.Weekday() == first.Weekday() || len(r.ByDay) > 0 && r.matchesWeekday(day)
+			if weekday && r.matchesMonth(day) {
+				days = append(days, day)
+			}
+		}
+		return weekStart, days
+
+	case Monthly:
+		month := time.Date(first.Year(), first.Month()+time.Month(k*interval), 1, 0, 0, 0, 0, time.UTC)
+		if !r.matchesMonth(month) {
+			return month, nil
+		}
+		return month, r.monthDays(month, first.Day())
+
+	default:
+		year := time.Date(first.Year()+k*interval, time.January, 1, 0, 0, 0, 0, time.UTC)
+		var days []time.Time
+		switch {
+		case len(r.ByMonth) > 0:
+			months := append([]time.Month(nil), r.ByMonth...)
+			sort.Slice(months, func(i, j int) bool { return months[i] < months[j] })
+			for _, m := range months {
+				days = append(days, r.monthDays(time.Date(year.Year(), m, 1, 0, 0, 0, 0, time.UTC), first.Day())...)
+			}
+		case len(r.ByMonthDay) > 0:
+			for m := time.January; m <= time.December; m++ {
+				days = append(days, r.monthDays(time.Date(year.Year(), m, 1, 0, 0, 0, 0, time.UTC), first.Day())...)
+			}
+		case len(r.ByDay) > 0:
+			days = weekdaysIn(year, year.AddDate(1, 0, 0), r.ByDay)
+		default:
+			if day := time.Date(year.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC); day.Month() == first.Month() {
+				days = []time.Time{day}
+			}
+		}
+		return year, days
+	}
+}
+
+// monthDays returns the days of the rule in the month starting at month, in
+// order. Without BYMONTHDAY or BYDAY, that is the given day of the month.
+func (r *Rule) monthDays(month time.Time, day int) []time.Time {
+	next := month.AddDate(0, 1, 0)
+	switch {
+	case len(r.ByMonthDay) > 0:
+		last := next.AddDate(0, 0, -1).Day()
+		var days []time.Time
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d += last + 1
+			}
+			if t := month.AddDate(0, 0, d-1); d >= 1 && d <= last && r.matchesWeekday(t) {
+				days = append(days, t)
+			}
+		}
+		return sortDays(days)
+	case len(r.ByDay) > 0:
+		return weekdaysIn(month, next, r.ByDay)
+	default:
+		if t := month.AddDate(0, 0, day-1); t.Month() == month.Month() {
+			return []time.Time{t}
+		}
+		return nil
+	}
+}
+
+// weekdaysIn returns the days from start up to end that match one of the
+// weekdays, where N counts the weekdays within that span, in order.
+func weekdaysIn(start, end time.Time, weekdays []Weekday) []time.Time {
+	var days []time.Time
+	for _, w := range weekdays {
+		first := start.AddDate(0, 0, (int(w.Weekday)-int(start.Weekday())+7)%7)
+		var all []time.Time
+		for d := first; d.Before(end); d = d.AddDate(0, 0, 7) {
+			all = append(all, d)
+		}
+		switch {
+		case w.N == 0:
+			days = append(days, all...)
+		case w.N > 0 && w.N <= len(all):
+			days = append(days, all[w.N-1])
+		case w.N < 0 && -w.N <= len(all):
+			days = append(days, all[len(all)+w.N])
+		}
+	}
+	return sortDays(days)
+}
+
+// setPositions applies BYSETPOS to the days of a period.
+func (r *Rule) setPositions(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		switch {
+		case pos > 0 && pos <= len(days):
+			selected = append(selected, days[pos-1])
+		case pos < 0 && -pos <= len(days):
+			selected = append(selected, days[len(days)+pos])
+		}
+	}
+	return sortDays(selected)
+}
+
+// matchesMonth reports whether day is in one of the months of BYMONTH.
+func (r *Rule) matchesMonth(day time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if day.Month() == m {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthDay reports whether day is one of the days of BYMONTHDAY.
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || d < 0 && d+last+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday reports whether day falls on one of the weekdays of BYDAY,
+// ignoring their N.
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, w := range r.ByDay {
+		if day.Weekday() == w.Weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDays sorts days and removes duplicates.
+func sortDays(days []time.Time) []time.Time {
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	unique := days[:0]
+	for i, d := range days {
+		if i == 0 || !d.Equal(days[i-1]) {
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
+
+// dateOf returns the date of t at midnight UTC.
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// inLocation returns the given time of day on day, a date at midnight UTC,
+// in the location of like.
+func inLocation(day time.Time, hour, minute, second int, like time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, like.Nanosecond(), like.Location())
+}
@@ -0,0 +1,285 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dates returns the dates at 9:00 in loc, given as year, month, day triples.
+func dates(loc *time.Location, ymd ...int) []time.Time {
+	var ts []time.Time
+	for i := 0; i+2 < len(ymd); i += 3 {
+		ts = append(ts, time.Date(ymd[i], time.Month(ymd[i+1]), ymd[i+2], 9, 0, 0, 0, loc))
+	}
+	return ts
+}
+
+// The examples of RFC 5545, section 3.8.5.3.
+func TestRuleBetween(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	start := func(year, month, day int) time.Time {
+		return time.Date(year, time.Month(month), day, 9, 0, 0, 0, newYork)
+	}
+	until := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		to       time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "daily for 10 occurrences",
+			rule:     "FREQ=DAILY;COUNT=10",
+			start:    start(1997, 9, 2),
+			to:       until,
+			expected: dates(newYork, 1997, 9, 2, 1997, 9, 3, 1997, 9, 4, 1997, 9, 5, 1997, 9, 6, 1997, 9, 7, 1997, 9, 8, 1997, 9, 9, 1997, 9, 10, 1997, 9, 11),
+		},
+		{
+			name:     "every 10 days, 5 occurrences",
+			rule:     "FREQ=DAILY;INTERVAL=10;COUNT=5",
+			start:    start(1997, 9, 2),
+			to:       until,
+			expected: dates(newYork, 1997, 9, 2, 1997, 9, 12, 1997, 9, 22, 1997, 10, 2, 1997, 10, 12),
+		},
+		{
+			name:     "every day in January",
+			rule:     "FREQ=DAILY;UNTIL=19980104T140000Z;BYMONTH=1",
+			start:    start(1997, 12, 30),
+			to:       until,
+			expected: dates(newYork, 1997, 12, 30, 1998, 1, 1, 1998, 1, 2, 1998, 1, 3, 1998, 1, 4),
+		},
+		{
+			name:     "weekly on Tuesday and Thursday for five weeks",
+			rule:     "FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			start:    start(1997, 9, 2),
+			to:       until,
+			expected: dates(newYork, 1997, 9, 2, 1997, 9, 4, 1997, 9, 9, 1997, 9, 11, 1997, 9, 16, 1997, 9, 18, 1997, 9, 23, 1997, 9, 25, 1997, 9, 30, 1997, 10, 2),
+		},
+		{
+			name:     "every other week with the week starting on Monday",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			start:    start(1997, 8, 5),
+			to:       until,
+			expected: dates(newYork, 1997, 8, 5, 1997, 8, 10, 1997, 8, 19, 1997, 8, 24),
+		},
+		{
+			name:     "every other week with the week starting on Sunday",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			start:    start(1997, 8, 5),
+			to:       until,
+			expected: dates(newYork, 1997, 8, 5, 1997, 8, 17, 1997, 8, 19, 1997, 8, 31),
+		},
+		{
+			name:  "monthly on the first Friday",
+			rule:  "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			start: start(1997, 9, 5),
+			to:    until,
+			expected: dates(newYork, 1997, 9, 5, 1997, 10, 3, 1997, 11, 7, 1997, 12, 5, 1998, 1, 2,
+				1998, 2, 6, 1998, 3, 6, 1998, 4, 3, 1998, 5, 1, 1998, 6, 5),
+		},
+		{
+			name:     "monthly on the second-to-last Monday",
+			rule:     "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			start:    start(1997, 9, 22),
+			to:       until,
+			expected: dates(newYork, 1997, 9, 22, 1997, 10, 20, 1997, 11, 17, 1997, 12, 22, 1998, 1, 19, 1998, 2, 16),
+		},
+		{
+			name:     "monthly on the third-to-last day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-3",
+			start:    start(1997, 9, 28),
+			to:       time.Date(1998, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 1997, 9, 28, 1997, 10, 29, 1997, 11, 28, 1997, 12, 29, 1998, 1, 29, 1998, 2, 26),
+		},
+		{
+			name:  "monthly on the 2nd and 15th",
+			rule:  "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			start: start(1997, 9, 2),
+			to:    until,
+			expected: dates(newYork, 1997, 9, 2, 1997, 9, 15, 1997, 10, 2, 1997, 10, 15, 1997, 11, 2,
+				1997, 11, 15, 1997, 12, 2, 1997, 12, 15, 1998, 1, 2, 1998, 1, 15),
+		},
+		{
+			name:     "last workday of the month",
+			rule:     "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			start:    start(1997, 9, 30),
+			to:       time.Date(1998, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 1997, 9, 30, 1997, 10, 31, 1997, 11, 28, 1997, 12, 31, 1998, 1, 30, 1998, 2, 27, 1998, 3, 31),
+		},
+		{
+			name:     "third of Tuesday, Wednesday and Thursday",
+			rule:     "FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			start:    start(1997, 9, 4),
+			to:       until,
+			expected: dates(newYork, 1997, 9, 4, 1997, 10, 7, 1997, 11, 6),
+		},
+		{
+			name:     "every Friday the 13th",
+			rule:     "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			start:    start(1997, 9, 2),
+			to:       until,
+			expected: dates(newYork, 1997, 9, 2, 1998, 2, 13, 1998, 3, 13, 1998, 11, 13, 1999, 8, 13),
+		},
+		{
+			name:  "yearly in June and July",
+			rule:  "FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			start: start(1997, 6, 10),
+			to:    time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 1997, 6, 10, 1997, 7, 10, 1998, 6, 10, 1998, 7, 10, 1999, 6, 10,
+				1999, 7, 10, 2000, 6, 10, 2000, 7, 10, 2001, 6, 10, 2001, 7, 10),
+		},
+		{
+			name:     "the 20th Monday of the year",
+			rule:     "FREQ=YEARLY;BYDAY=20MO",
+			start:    start(1997, 5, 19),
+			to:       until,
+			expected: dates(newYork, 1997, 5, 19, 1998, 5, 18, 1999, 5, 17),
+		},
+		{
+			name:     "US presidential election day",
+			rule:     "FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8",
+			start:    start(1996, 11, 5),
+			to:       time.Date(2005, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 1996, 11, 5, 2000, 11, 7, 2004, 11, 2),
+		},
+		{
+			name:     "February 29 only in leap years",
+			rule:     "FREQ=YEARLY;COUNT=3",
+			start:    start(2024, 2, 29),
+			to:       time.Date(2040, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 2024, 2, 29, 2028, 2, 29, 2032, 2, 29),
+		},
+		{
+			name:     "the 31st only in long months",
+			rule:     "FREQ=MONTHLY;COUNT=4",
+			start:    start(2026, 1, 31),
+			to:       time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 2026, 1, 31, 2026, 3, 31, 2026, 5, 31, 2026, 7, 31),
+		},
+		{
+			name:     "across a change to summer time",
+			rule:     "FREQ=WEEKLY",
+			start:    start(2026, 3, 1),
+			to:       time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC),
+			expected: dates(newYork, 2026, 3, 1, 2026, 3, 8, 2026, 3, 15),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, r.Between(tt.start, tt.start, tt.to))
+			}
+		})
+	}
+}
+
+func TestRuleBetweenRange(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;COUNT=5")
+	assert.NoError(t, err)
+	start := time.Date(2026, time.October, 5, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.UTC)
+
+	// The occurrences before the range count towards COUNT.
+	assert.Equal(t, dates(time.UTC, 2026, 10, 12, 2026, 10, 19, 2026, 10, 26, 2026, 11, 2),
+		r.Between(start, from, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Empty(t, r.Between(start, from, from))
+}
+
+func TestRuleSynchronized(t *testing.T) {
+	tests := []struct {
+		rule     string
+		start    time.Time
+		expected bool
+	}{
+		{rule: "FREQ=WEEKLY;INTERVAL=2", start: time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC), expected: true},
+		{rule: "FREQ=MONTHLY;BYDAY=-1FR", start: time.Date(2026, time.October, 30, 9, 0, 0, 0, time.UTC), expected: true},
+		{rule: "FREQ=MONTHLY;BYDAY=-1FR", start: time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC), expected: false},
+		{rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=1", start: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), expected: false},
+		{rule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", start: time.Date(2026, time.October, 30, 0, 0, 0, 0, time.UTC), expected: true},
+		{rule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", start: time.Date(2026, time.October, 29, 0, 0, 0, 0, time.UTC), expected: false},
+	}
+
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, r.Synchronized(tt.start), "%s from %s", tt.rule, tt.start.Format(time.DateOnly))
+	}
+}
+
+func TestSetBetween(t *testing.T) {
+	daily, err := Parse("FREQ=DAILY;COUNT=5")
+	assert.NoError(t, err)
+	s := &Set{
+		Start:   time.Date(2026, time.October, 5, 9, 0, 0, 0, time.UTC),
+		Rules:   []*Rule{daily},
+		RDates:  dates(time.UTC, 2026, 10, 7, 2026, 10, 20),
+		ExDates: dates(time.UTC, 2026, 10, 6),
+	}
+	to := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, dates(time.UTC, 2026, 10, 5, 2026, 10, 7, 2026, 10, 8, 2026, 10, 9, 2026, 10, 20),
+		s.Between(s.Start, to))
+
+	s.Rules = nil
+	assert.Equal(t, dates(time.UTC, 2026, 10, 5, 2026, 10, 7, 2026, 10, 20), s.Between(s.Start, to))
+}
+
+func TestParse(t *testing.T) {
+	r, err := Parse("RRULE:FREQ=MONTHLY;INTERVAL=2;UNTIL=20261231;BYMONTH=1,7;BYDAY=MO,-1FR,+2TU;BYSETPOS=1,-1;WKST=SU")
+	assert.NoError(t, err)
+	assert.Equal(t, &Rule{
+		Freq:      Monthly,
+		Interval:  2,
+		Until:     time.Date(2026, time.December, 31, 23, 59, 59, 0, time.UTC),
+		ByMonth:   []time.Month{time.January, time.July},
+		ByDay:     []Weekday{{Weekday: time.Monday}, {Weekday: time.Friday, N: -1}, {Weekday: time.Tuesday, N: 2}},
+		BySetPos:  []int{1, -1},
+		WeekStart: time.Sunday,
+	}, r)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;UNTIL=20261231T235959Z;BYMONTH=1,7;BYDAY=MO,-1FR,2TU;BYSETPOS=1,-1;WKST=SU", r.String())
+
+	r, err = Parse("FREQ=yearly;BYMONTHDAY=-1;COUNT=3")
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=YEARLY;COUNT=3;BYMONTHDAY=-1", r.String())
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected string
+	}{
+		{"", `invalid rule part ""`},
+		{"COUNT=3", `rule "COUNT=3" has no FREQ`},
+		{"FREQ=HOURLY", "FREQ=HOURLY is not supported"},
+		{"FREQ=SOMETIMES", `invalid FREQ "SOMETIMES"`},
+		{"FREQ=DAILY;FREQ=WEEKLY", "FREQ given twice"},
+		{"FREQ=DAILY;INTERVAL=0", `invalid INTERVAL "0"`},
+		{"FREQ=DAILY;COUNT=x", `invalid COUNT "x"`},
+		{"FREQ=DAILY;UNTIL=tomorrow", `invalid UNTIL "tomorrow"`},
+		{"FREQ=YEARLY;BYMONTH=13", `invalid BYMONTH "13"`},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", `invalid BYMONTHDAY "0"`},
+		{"FREQ=MONTHLY;BYDAY=XX", `invalid weekday "XX"`},
+		{"FREQ=MONTHLY;BYDAY=6", `invalid weekday "6"`},
+		{"FREQ=YEARLY;BYDAY=54MO", `invalid weekday "54MO"`},
+		{"FREQ=WEEKLY;WKST=1MO", `invalid WKST "1MO"`},
+		{"FREQ=YEARLY;BYWEEKNO=20", "BYWEEKNO is not supported"},
+		{"FREQ=DAILY;BYEASTER=0", `unknown rule part "BYEASTER"`},
+		{"FREQ=DAILY;COUNT=3;UNTIL=20261231", "COUNT and UNTIL cannot both be given"},
+		{"FREQ=WEEKLY;BYMONTHDAY=1", "BYMONTHDAY cannot be used with FREQ=WEEKLY"},
+		{"FREQ=WEEKLY;BYDAY=1MO", "BYDAY 1MO needs FREQ=MONTHLY or FREQ=YEARLY"},
+		{"FREQ=MONTHLY;BYSETPOS=1", "BYSETPOS needs another BY rule part"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := Parse(tt.rule)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
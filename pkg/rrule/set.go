@@ -0,0 +1,54 @@
+package rrule
+
+import (
+	"sort"
+	"time"
+)
+
+// Set is the recurrence of an iCalendar event: its start, the rules (RRULE)
+// and extra dates (RDATE) it recurs on and the dates excluded from those
+// (EXDATE).
+type Set struct {
+	Start   time.Time
+	Rules   []*Rule
+	RDates  []time.Time
+	ExDates []time.Time
+}
+
+// Between returns the occurrences of s from from up to but not including to,
+// in order and without duplicates. Start is an occurrence unless excluded.
+func (s *Set) Between(from, to time.Time) []time.Time {
+	inRange := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+
+	var occurrences []time.Time
+	if len(s.Rules) == 0 && inRange(s.Start) {
+		occurrences = append(occurrences, s.Start)
+	}
+	for _, r := range s.Rules {
+		occurrences = append(occurrences, r.Between(s.Start, from, to)...)
+	}
+	for _, t := range s.RDates {
+		if inRange(t) {
+			occurrences = append(occurrences, t)
+		}
+	}
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+
+	unique := occurrences[:0]
+	for i, t := range occurrences {
+		if (i == 0 || !t.Equal(occurrences[i-1])) && !s.excluded(t) {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// excluded reports whether t is one of the ExDates.
+func (s *Set) excluded(t time.Time) bool {
+	for _, ex := range s.ExDates {
+		if t.Equal(ex) {
+			return true
+		}
+	}
+	return false
+}
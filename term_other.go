@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package main
+
+import "os"
+
+// terminalWidth returns 0, as terminal sizes cannot be queried on this
+// platform.
+func terminalWidth(*os.File) int {
+	return 0
+}